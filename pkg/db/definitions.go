@@ -0,0 +1,492 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/BurningIceCube/quizine/pkg/quiz"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqlRunner is implemented by both *sql.DB and *sql.Tx.
+type sqlRunner interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+type DefinitionStore struct {
+	db *sql.DB
+}
+
+func NewDefinitionStore(dbPath string) (*DefinitionStore, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	// Create tables if they don't exist
+	if err := createDefinitionTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create tables: %v", err)
+	}
+
+	return &DefinitionStore{db: db}, nil
+}
+
+func createDefinitionTables(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS quiz_definitions (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		description TEXT NOT NULL,
+		instructions TEXT NOT NULL,
+		time_limit INTEGER NOT NULL,
+		passing_score REAL NOT NULL,
+		scoring_method TEXT NOT NULL,
+		scoring_config TEXT NOT NULL DEFAULT '',
+		shuffle_questions BOOLEAN NOT NULL,
+		shuffle_answers BOOLEAN NOT NULL,
+		max_tries INTEGER NOT NULL DEFAULT 0,
+		retry_decay TEXT NOT NULL DEFAULT '',
+		late_policy TEXT NOT NULL DEFAULT '',
+		navigation TEXT NOT NULL DEFAULT '',
+		hint_penalty TEXT NOT NULL DEFAULT '',
+		hint_penalty_amount REAL NOT NULL DEFAULT 0,
+		pools TEXT NOT NULL DEFAULT '',
+		minimize_overlap BOOLEAN NOT NULL DEFAULT 0,
+		adaptive TEXT NOT NULL DEFAULT '',
+		attempt_id TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS quiz_definition_questions (
+		definition_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		question_id TEXT NOT NULL,
+		FOREIGN KEY (definition_id) REFERENCES quiz_definitions(id),
+		FOREIGN KEY (question_id) REFERENCES questions(id),
+		PRIMARY KEY (definition_id, position)
+	);`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return err
+	}
+
+	// Databases created before questions could be retried
+	if err := addColumn(db, "quiz_definitions", "max_tries", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quiz_definitions", "retry_decay", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before time limits were enforced
+	if err := addColumn(db, "quiz_definitions", "late_policy", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before questions could be drawn from pools
+	if err := addColumn(db, "quiz_definitions", "pools", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quiz_definitions", "minimize_overlap", "BOOLEAN NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Databases created before attempts could be navigated freely
+	if err := addColumn(db, "quiz_definitions", "navigation", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before hints cost points
+	if err := addColumn(db, "quiz_definitions", "hint_penalty", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quiz_definitions", "hint_penalty_amount", "REAL NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Databases created before scoring strategies could be configured
+	if err := addColumn(db, "quiz_definitions", "scoring_config", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before quizzes could adapt to the taker
+	if err := addColumn(db, "quiz_definitions", "adaptive", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before the implicit definitions of attempts were
+	// told apart, which stored them under the attempt's ID
+	split, err := columnExists(db, "quiz_definitions", "attempt_id")
+	if err != nil {
+		return fmt.Errorf("failed to inspect table quiz_definitions: %v", err)
+	}
+	if split {
+		return nil
+	}
+	if err := addColumn(db, "quiz_definitions", "attempt_id", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	return markAdHocDefinitions(db)
+}
+
+// markAdHocDefinitions records which attempt owns each stored implicit
+// definition: those sharing their ID with the attempt that uses them.
+func markAdHocDefinitions(db *sql.DB) error {
+	exists, err := columnExists(db, "quizzes", "definition_id")
+	if err != nil || !exists {
+		return err
+	}
+	_, err = db.Exec("UPDATE quiz_definitions SET attempt_id = id WHERE id IN (SELECT id FROM quizzes WHERE definition_id = id)")
+	return err
+}
+
+// encodeScoring returns the method and JSON settings of a scoring strategy.
+// A nil strategy is stored as the definition's method without settings.
+// Only the built-in strategies can be stored.
+func encodeScoring(s quiz.ScoringStrategy) (string, string, error) {
+	if s == nil {
+		return "", "", nil
+	}
+	if quiz.NewScoringStrategy(s.Method()) == nil {
+		return "", "", fmt.Errorf("scoring strategy %s cannot be stored", s.Method())
+	}
+	config, err := json.Marshal(s)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal scoring strategy: %v", err)
+	}
+	return string(s.Method()), string(config), nil
+}
+
+// decodeScoring rebuilds a scoring strategy stored by encodeScoring. It
+// returns nil if no settings were stored.
+func decodeScoring(method, config string) (quiz.ScoringStrategy, error) {
+	if config == "" {
+		return nil, nil
+	}
+	s := quiz.NewScoringStrategy(quiz.ScoringMethod(method))
+	if s == nil {
+		return nil, fmt.Errorf("unknown scoring method %s", method)
+	}
+	if err := json.Unmarshal([]byte(config), s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scoring strategy: %v", err)
+	}
+	return s, nil
+}
+
+func (ds *DefinitionStore) Close() error {
+	return ds.db.Close()
+}
+
+// SaveDefinition creates or updates a quiz definition and its question references.
+func (ds *DefinitionStore) SaveDefinition(d *quiz.QuizDefinition) error {
+	tx, err := ds.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveDefinition(tx, d); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// definitionStored reports whether a definition with d's ID is stored. It
+// returns an error if that definition belongs to a different attempt, or to
+// none when d belongs to one, so implicit definitions never replace or
+// stand in for others.
+func definitionStored(tx *sql.Tx, d *quiz.QuizDefinition) (bool, error) {
+	var attemptID string
+	err := tx.QueryRow("SELECT attempt_id FROM quiz_definitions WHERE id = ?", d.Id).Scan(&attemptID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check quiz definition: %v", err)
+	}
+	if attemptID != d.AttemptID {
+		return true, fmt.Errorf("quiz definition ID %s is already taken", d.Id)
+	}
+	return true, nil
+}
+
+// saveDefinition writes d and its question references within tx.
+func saveDefinition(tx *sql.Tx, d *quiz.QuizDefinition) error {
+	if _, err := definitionStored(tx, d); err != nil {
+		return err
+	}
+
+	scoringMethod := d.ScoringMethod
+	if scoringMethod == "" {
+		scoringMethod = quiz.DIFFICULTY_WEIGHTED
+	}
+	method, scoringConfig, err := encodeScoring(d.Scoring)
+	if err != nil {
+		return err
+	}
+	if method != "" {
+		scoringMethod = quiz.ScoringMethod(method)
+	}
+
+	var retryDecay string
+	if len(d.RetryDecay) > 0 {
+		retryDecayJSON, err := json.Marshal(d.RetryDecay)
+		if err != nil {
+			return fmt.Errorf("failed to marshal retry decay: %v", err)
+		}
+		retryDecay = string(retryDecayJSON)
+	}
+
+	var pools string
+	if len(d.Pools) > 0 {
+		poolsJSON, err := json.Marshal(d.Pools)
+		if err != nil {
+			return fmt.Errorf("failed to marshal pools: %v", err)
+		}
+		pools = string(poolsJSON)
+	}
+
+	var adaptive string
+	if d.Adaptive != nil {
+		adaptiveJSON, err := json.Marshal(d.Adaptive)
+		if err != nil {
+			return fmt.Errorf("failed to marshal adaptive policy: %v", err)
+		}
+		adaptive = string(adaptiveJSON)
+	}
+
+	query := `
+	INSERT INTO quiz_definitions (id, title, description, instructions, time_limit, passing_score, scoring_method, scoring_config, shuffle_questions, shuffle_answers, max_tries, retry_decay, late_policy, navigation, hint_penalty, hint_penalty_amount, pools, minimize_overlap, adaptive, attempt_id, status, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		title = excluded.title,
+		description = excluded.description,
+		instructions = excluded.instructions,
+		time_limit = excluded.time_limit,
+		passing_score = excluded.passing_score,
+		scoring_method = excluded.scoring_method,
+		scoring_config = excluded.scoring_config,
+		shuffle_questions = excluded.shuffle_questions,
+		shuffle_answers = excluded.shuffle_answers,
+		max_tries = excluded.max_tries,
+		retry_decay = excluded.retry_decay,
+		late_policy = excluded.late_policy,
+		navigation = excluded.navigation,
+		hint_penalty = excluded.hint_penalty,
+		hint_penalty_amount = excluded.hint_penalty_amount,
+		pools = excluded.pools,
+		minimize_overlap = excluded.minimize_overlap,
+		adaptive = excluded.adaptive,
+		status = excluded.status,
+		updated_at = excluded.updated_at`
+
+	_, err = tx.Exec(query,
+		d.Id,
+		d.Title,
+		d.Description,
+		d.Instructions,
+		d.TimeLimit.Milliseconds(),
+		d.PassingScore,
+		string(scoringMethod),
+		scoringConfig,
+		d.ShuffleQuestions,
+		d.ShuffleAnswers,
+		d.MaxTries,
+		retryDecay,
+		string(d.LatePolicy),
+		string(d.Navigation),
+		string(d.HintPenalty.Kind),
+		d.HintPenalty.Amount,
+		pools,
+		d.MinimizeOverlap,
+		adaptive,
+		d.AttemptID,
+		string(d.Status),
+		d.CreatedAt,
+		d.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save quiz definition: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM quiz_definition_questions WHERE definition_id = ?", d.Id)
+	if err != nil {
+		return fmt.Errorf("failed to clear definition questions: %v", err)
+	}
+
+	for i, questionID := range d.QuestionIDs {
+		_, err = tx.Exec("INSERT INTO quiz_definition_questions (definition_id, position, question_id) VALUES (?, ?, ?)",
+			d.Id, i, questionID)
+		if err != nil {
+			return fmt.Errorf("failed to save definition question: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (ds *DefinitionStore) GetDefinition(id string) (*quiz.QuizDefinition, error) {
+	return getDefinition(ds.db, id)
+}
+
+// getDefinition reads a definition and its question references.
+func getDefinition(db sqlRunner, id string) (*quiz.QuizDefinition, error) {
+	query := `
+	SELECT title, description, instructions, time_limit, passing_score, scoring_method, scoring_config, shuffle_questions, shuffle_answers, max_tries, retry_decay, late_policy, navigation, hint_penalty, hint_penalty_amount, pools, minimize_overlap, adaptive, attempt_id, status, created_at, updated_at
+	FROM quiz_definitions
+	WHERE id = ?`
+
+	var (
+		d             = &quiz.QuizDefinition{Id: id}
+		timeLimit     int64
+		scoringMethod string
+		scoringConfig string
+		retryDecay    string
+		latePolicy    string
+		navigation    string
+		hintPenalty   string
+		pools         string
+		adaptive      string
+		status        string
+	)
+
+	err := db.QueryRow(query, id).Scan(
+		&d.Title,
+		&d.Description,
+		&d.Instructions,
+		&timeLimit,
+		&d.PassingScore,
+		&scoringMethod,
+		&scoringConfig,
+		&d.ShuffleQuestions,
+		&d.ShuffleAnswers,
+		&d.MaxTries,
+		&retryDecay,
+		&latePolicy,
+		&navigation,
+		&hintPenalty,
+		&d.HintPenalty.Amount,
+		&pools,
+		&d.MinimizeOverlap,
+		&adaptive,
+		&d.AttemptID,
+		&status,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz definition: %v", err)
+	}
+	d.TimeLimit = time.Duration(timeLimit) * time.Millisecond
+	d.ScoringMethod = quiz.ScoringMethod(scoringMethod)
+	d.Scoring, err = decodeScoring(scoringMethod, scoringConfig)
+	if err != nil {
+		return nil, err
+	}
+	d.LatePolicy = quiz.LatePolicy(latePolicy)
+	d.Navigation = quiz.NavigationMode(navigation)
+	d.HintPenalty.Kind = quiz.PenaltyKind(hintPenalty)
+	d.Status = quiz.DefinitionStatus(status)
+	if retryDecay != "" {
+		if err := json.Unmarshal([]byte(retryDecay), &d.RetryDecay); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal retry decay: %v", err)
+		}
+	}
+	if pools != "" {
+		if err := json.Unmarshal([]byte(pools), &d.Pools); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pools: %v", err)
+		}
+	}
+	if adaptive != "" {
+		d.Adaptive = &quiz.AdaptivePolicy{}
+		if err := json.Unmarshal([]byte(adaptive), d.Adaptive); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal adaptive policy: %v", err)
+		}
+	}
+
+	rows, err := db.Query("SELECT question_id FROM quiz_definition_questions WHERE definition_id = ? ORDER BY position", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get definition questions: %v", err)
+	}
+	defer rows.Close()
+
+	d.QuestionIDs = []string{}
+	for rows.Next() {
+		var questionID string
+		if err := rows.Scan(&questionID); err != nil {
+			return nil, fmt.Errorf("failed to scan question ID: %v", err)
+		}
+		d.QuestionIDs = append(d.QuestionIDs, questionID)
+	}
+
+	return d, rows.Err()
+}
+
+// DeleteDefinition removes a definition. Definitions that have been taken
+// should be archived instead so their attempts keep their rules.
+func (ds *DefinitionStore) DeleteDefinition(id string) error {
+	tx, err := ds.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM quiz_definition_questions WHERE definition_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete definition questions: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM quiz_definitions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete quiz definition: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListDefinitions returns the definitions with the given status, or every
+// definition if status is empty, oldest first. The implicit definitions of
+// attempts started with quiz.NewQuiz are left out.
+func (ds *DefinitionStore) ListDefinitions(status quiz.DefinitionStatus) ([]*quiz.QuizDefinition, error) {
+	rows, err := ds.db.Query("SELECT id FROM quiz_definitions WHERE attempt_id = '' AND (? = '' OR status = ?) ORDER BY created_at", string(status), string(status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quiz definitions: %v", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan definition ID: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list quiz definitions: %v", err)
+	}
+
+	var definitions []*quiz.QuizDefinition
+	for _, id := range ids {
+		d, err := ds.GetDefinition(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get quiz definition %s: %v", id, err)
+		}
+		definitions = append(definitions, d)
+	}
+
+	return definitions, nil
+}
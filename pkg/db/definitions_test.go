@@ -0,0 +1,87 @@
+package db
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BurningIceCube/quizine/pkg/quiz"
+)
+
+func TestDefinitionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_definitions.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer store.Close()
+
+	d := quiz.NewQuizDefinition("def1", "History 101", []string{"q2", "q1"})
+	d.Description = "Dates and names"
+	d.Instructions = "Answer every question"
+	d.TimeLimit = 10 * time.Minute
+	d.PassingScore = 75
+	d.ShuffleAnswers = true
+	if err := store.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	retrieved, err := store.GetDefinition("def1")
+	if err != nil {
+		t.Fatalf("Failed to get definition: %v", err)
+	}
+	if retrieved.Title != "History 101" || retrieved.Description != "Dates and names" || retrieved.Instructions != "Answer every question" {
+		t.Errorf("Expected text fields to round trip, got %+v", retrieved)
+	}
+	if len(retrieved.QuestionIDs) != 2 || retrieved.QuestionIDs[0] != "q2" || retrieved.QuestionIDs[1] != "q1" {
+		t.Errorf("Expected question IDs [q2 q1], got %v", retrieved.QuestionIDs)
+	}
+	if retrieved.TimeLimit != 10*time.Minute {
+		t.Errorf("Expected time limit 10m, got %v", retrieved.TimeLimit)
+	}
+	if retrieved.PassingScore != 75 {
+		t.Errorf("Expected passing score 75, got %v", retrieved.PassingScore)
+	}
+	if retrieved.ShuffleQuestions || !retrieved.ShuffleAnswers {
+		t.Errorf("Expected only answers to be shuffled, got %v and %v", retrieved.ShuffleQuestions, retrieved.ShuffleAnswers)
+	}
+	if retrieved.Status != quiz.DRAFT || retrieved.ScoringMethod != quiz.DIFFICULTY_WEIGHTED {
+		t.Errorf("Expected DRAFT and DIFFICULTY_WEIGHTED, got %s and %s", retrieved.Status, retrieved.ScoringMethod)
+	}
+
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := store.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to update definition: %v", err)
+	}
+
+	drafts, err := store.ListDefinitions(quiz.DRAFT)
+	if err != nil {
+		t.Fatalf("Failed to list definitions: %v", err)
+	}
+	if len(drafts) != 0 {
+		t.Errorf("Expected no drafts, got %d", len(drafts))
+	}
+	published, err := store.ListDefinitions(quiz.PUBLISHED)
+	if err != nil {
+		t.Fatalf("Failed to list definitions: %v", err)
+	}
+	if len(published) != 1 || published[0].Id != "def1" {
+		t.Errorf("Expected def1 to be published, got %v", published)
+	}
+
+	if err := store.DeleteDefinition("def1"); err != nil {
+		t.Fatalf("Failed to delete definition: %v", err)
+	}
+	if _, err := store.GetDefinition("def1"); err == nil {
+		t.Error("Expected error when getting deleted definition")
+	}
+}
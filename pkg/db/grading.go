@@ -0,0 +1,157 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/BurningIceCube/quizine/pkg/quiz"
+)
+
+// PendingResponse is a manually graded response waiting for a grader.
+// QuestionIndex is the position of the question in the quiz, which tells
+// apart the occurrences of a question asked more than once.
+type PendingResponse struct {
+	QuizID        string
+	QuestionIndex int
+	QuestionID    string
+	Answer        string
+}
+
+// ManualGrade is the score and feedback a grader gave a response.
+type ManualGrade struct {
+	QuizID        string
+	QuestionIndex int
+	QuestionID    string
+	GraderID      string
+	Points        float64
+	Feedback      string
+	GradedAt      time.Time
+}
+
+// manualGradesTableSQL creates the table holding the grades given to
+// manually graded responses. question_index is the position of the graded
+// question in quiz_questions.
+const manualGradesTableSQL = `
+	CREATE TABLE IF NOT EXISTS manual_grades (
+		quiz_id TEXT NOT NULL,
+		question_index INTEGER NOT NULL,
+		question_id TEXT NOT NULL,
+		grader_id TEXT NOT NULL,
+		points REAL NOT NULL,
+		feedback TEXT NOT NULL,
+		graded_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (quiz_id) REFERENCES quizzes(id),
+		FOREIGN KEY (question_id) REFERENCES questions(id),
+		PRIMARY KEY (quiz_id, question_index)
+	);`
+
+func createGradingTables(db *sql.DB) error {
+	if _, err := db.Exec(manualGradesTableSQL); err != nil {
+		return err
+	}
+
+	// Databases created before grades were kept per question position, whose
+	// keys allowed one grade per question and quiz
+	return rebuildTable(db, "manual_grades", "question_index", manualGradesTableSQL, `
+	INSERT INTO manual_grades (quiz_id, question_index, question_id, grader_id, points, feedback, graded_at)
+	SELECT g.quiz_id, COALESCE(
+		(SELECT MIN(position) FROM quiz_questions q WHERE q.quiz_id = g.quiz_id AND q.question_id = g.question_id), 0),
+		g.question_id, g.grader_id, g.points, g.feedback, g.graded_at
+	FROM manual_grades_old g`)
+}
+
+// ListUngradedResponses returns every stored response that still needs
+// manual grading, oldest quiz first.
+func (qs *QuizStore) ListUngradedResponses() ([]PendingResponse, error) {
+	query := `
+	SELECT h.quiz_id, h.question_index, h.question_id, h.answer
+	FROM quiz_history h
+	JOIN quizzes q ON q.id = h.quiz_id
+	WHERE h.pending = 1
+	ORDER BY q.created_at, q.rowid, h.seq`
+
+	rows, err := qs.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ungraded responses: %v", err)
+	}
+	defer rows.Close()
+
+	var responses []PendingResponse
+	for rows.Next() {
+		var r PendingResponse
+		if err := rows.Scan(&r.QuizID, &r.QuestionIndex, &r.QuestionID, &r.Answer); err != nil {
+			return nil, fmt.Errorf("failed to scan ungraded response: %v", err)
+		}
+		responses = append(responses, r)
+	}
+
+	return responses, rows.Err()
+}
+
+// RecordGrade stores a grader's points and feedback for the pending
+// response to the question at questionIndex and applies the points to the
+// quiz, finalizing its score and result once nothing is left to grade. The
+// grade is dated by the store's clock. graderID must be a stored user with
+// the GRADER role, otherwise the error wraps quiz.ErrPermissionDenied.
+func (qs *QuizStore) RecordGrade(quizID string, questionIndex int, graderID string, points float64, feedback string) error {
+	grader, err := getUser(qs.db, "id", graderID)
+	if err != nil {
+		return fmt.Errorf("failed to get grader %s: %v", graderID, err)
+	}
+	if err := grader.Authorize(quiz.GRADER); err != nil {
+		return err
+	}
+
+	q, err := qs.GetQuiz(quizID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.GradeResponse(questionIndex, points, feedback); err != nil {
+		return fmt.Errorf("failed to grade response: %v", err)
+	}
+	questionID := q.GetQuestions()[questionIndex].GetID()
+
+	tx, err := qs.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("INSERT INTO manual_grades (quiz_id, question_index, question_id, grader_id, points, feedback, graded_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		quizID, questionIndex, questionID, graderID, points, feedback, qs.now())
+	if err != nil {
+		return fmt.Errorf("failed to save manual grade: %v", err)
+	}
+
+	// The grade changes the score of an expired attempt's stored result
+	if err := deleteResult(tx, quizID); err != nil {
+		return err
+	}
+	if err := saveQuiz(tx, q); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetManualGrades returns the grades recorded for a quiz's responses.
+func (qs *QuizStore) GetManualGrades(quizID string) ([]ManualGrade, error) {
+	rows, err := qs.db.Query("SELECT question_index, question_id, grader_id, points, feedback, graded_at FROM manual_grades WHERE quiz_id = ? ORDER BY graded_at, question_index", quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get manual grades: %v", err)
+	}
+	defer rows.Close()
+
+	var grades []ManualGrade
+	for rows.Next() {
+		g := ManualGrade{QuizID: quizID}
+		if err := rows.Scan(&g.QuestionIndex, &g.QuestionID, &g.GraderID, &g.Points, &g.Feedback, &g.GradedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan manual grade: %v", err)
+		}
+		grades = append(grades, g)
+	}
+
+	return grades, rows.Err()
+}
@@ -0,0 +1,223 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BurningIceCube/quizine/pkg/quiz"
+)
+
+func TestGradingQueue(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_grading.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	defer store.Close()
+
+	questions := []quiz.Questioner{
+		&quiz.Essay{Id: "e1", Prompt: "Explain recursion", Difficulty: 5, Rubric: "Mentions a base case"},
+		&quiz.AudioResponse{Id: "a1", Prompt: "Pronounce 'Eichhörnchen'", Difficulty: 2},
+	}
+	for _, q := range questions {
+		if err := store.questionStore.SaveQuestion(q); err != nil {
+			t.Fatalf("Failed to save question: %v", err)
+		}
+	}
+
+	q := quiz.NewQuiz("quiz1", questions)
+	q.SubmitAnswer("A function that calls itself until it reaches a base case.")
+	q.NextQuestion()
+	q.SubmitAnswer("s3://recordings/quiz1/a1.ogg")
+	q.NextQuestion()
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	// Test ListUngradedResponses
+	pending, err := store.ListUngradedResponses()
+	if err != nil {
+		t.Fatalf("Failed to list ungraded responses: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("Expected 2 ungraded responses, got %d", len(pending))
+	}
+	if pending[0].QuizID != "quiz1" || pending[0].QuestionID != "e1" {
+		t.Errorf("Expected first response for quiz1/e1, got %s/%s", pending[0].QuizID, pending[0].QuestionID)
+	}
+	if pending[1].Answer != "s3://recordings/quiz1/a1.ogg" {
+		t.Errorf("Expected audio reference as answer, got '%s'", pending[1].Answer)
+	}
+
+	// Test RecordGrade
+	saveGraders(t, dbPath, "grader1", "grader2")
+	if err := store.RecordGrade("quiz1", 0, "nobody", 4, "Good"); err == nil {
+		t.Error("Expected error when the grader does not exist")
+	}
+	if err := store.RecordGrade("quiz1", 0, "taker1", 4, "Good"); !errors.Is(err, quiz.ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied for a user who is not a grader, got %v", err)
+	}
+	gradedAt := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	store.SetClock(quiz.NewFakeClock(gradedAt))
+	if err := store.RecordGrade("quiz1", 0, "grader1", 4, "Good, but no example"); err != nil {
+		t.Fatalf("Failed to record grade: %v", err)
+	}
+	if err := store.RecordGrade("quiz1", 0, "grader1", 5, "Regrade"); err == nil {
+		t.Error("Expected error when grading a response twice")
+	}
+
+	retrieved, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if retrieved.GetStatus() != quiz.AWAITING_GRADING {
+		t.Errorf("Expected status AWAITING_GRADING, got %s", retrieved.GetStatus())
+	}
+	if retrieved.GetScore() != 4 {
+		t.Errorf("Expected score 4, got %v", retrieved.GetScore())
+	}
+
+	pending, err = store.ListUngradedResponses()
+	if err != nil {
+		t.Fatalf("Failed to list ungraded responses: %v", err)
+	}
+	if len(pending) != 1 || pending[0].QuestionID != "a1" || pending[0].QuestionIndex != 1 {
+		t.Fatalf("Expected only a1 to remain ungraded, got %v", pending)
+	}
+
+	// Grading the last response finalizes the quiz
+	if err := store.RecordGrade("quiz1", 1, "grader2", 2, "Perfect"); err != nil {
+		t.Fatalf("Failed to record grade: %v", err)
+	}
+	retrieved, err = store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if retrieved.GetStatus() != quiz.FINISHED {
+		t.Errorf("Expected status FINISHED, got %s", retrieved.GetStatus())
+	}
+	if retrieved.GetScore() != 6 {
+		t.Errorf("Expected score 6, got %v", retrieved.GetScore())
+	}
+	if retrieved.GetCorrectCount() != 1 {
+		t.Errorf("Expected 1 correct answer, got %d", retrieved.GetCorrectCount())
+	}
+
+	// Test GetManualGrades
+	grades, err := store.GetManualGrades("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get manual grades: %v", err)
+	}
+	if len(grades) != 2 {
+		t.Fatalf("Expected 2 grades, got %d", len(grades))
+	}
+	if grades[0].GraderID != "grader1" || grades[0].Points != 4 || grades[0].Feedback != "Good, but no example" {
+		t.Errorf("Unexpected first grade: %+v", grades[0])
+	}
+	if !grades[0].GradedAt.Equal(gradedAt) {
+		t.Errorf("Expected graded time %v, got %v", gradedAt, grades[0].GradedAt)
+	}
+}
+
+func TestGradingRepeatedQuestion(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_grading_repeated.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	defer store.Close()
+
+	essay := &quiz.Essay{Id: "e1", Prompt: "Explain recursion", Difficulty: 5}
+	if err := store.questionStore.SaveQuestion(essay); err != nil {
+		t.Fatalf("Failed to save question: %v", err)
+	}
+
+	q := quiz.NewQuiz("quiz1", []quiz.Questioner{essay, essay})
+	q.SubmitAnswer("A function that calls itself.")
+	q.NextQuestion()
+	q.SubmitAnswer("A function that calls itself until it reaches a base case.")
+	q.NextQuestion()
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	pending, err := store.ListUngradedResponses()
+	if err != nil {
+		t.Fatalf("Failed to list ungraded responses: %v", err)
+	}
+	if len(pending) != 2 || pending[0].QuestionIndex != 0 || pending[1].QuestionIndex != 1 {
+		t.Fatalf("Expected pending responses for questions 0 and 1, got %+v", pending)
+	}
+
+	saveGraders(t, dbPath, "grader1")
+	if err := store.RecordGrade("quiz1", 1, "grader1", 5, "Complete"); err != nil {
+		t.Fatalf("Failed to grade the second occurrence: %v", err)
+	}
+	if err := store.RecordGrade("quiz1", 0, "grader1", 2, "No base case"); err != nil {
+		t.Fatalf("Failed to grade the first occurrence: %v", err)
+	}
+
+	retrieved, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if retrieved.GetStatus() != quiz.FINISHED {
+		t.Errorf("Expected status FINISHED, got %s", retrieved.GetStatus())
+	}
+	if retrieved.GetScore() != 7 {
+		t.Errorf("Expected score 7, got %v", retrieved.GetScore())
+	}
+
+	grades, err := store.GetManualGrades("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get manual grades: %v", err)
+	}
+	if len(grades) != 2 {
+		t.Fatalf("Expected 2 grades, got %d", len(grades))
+	}
+	for _, grade := range grades {
+		if grade.QuestionID != "e1" {
+			t.Errorf("Expected grade for e1, got %s", grade.QuestionID)
+		}
+		if want := map[int]float64{0: 2, 1: 5}[grade.QuestionIndex]; grade.Points != want {
+			t.Errorf("Expected %v points for question %d, got %v", want, grade.QuestionIndex, grade.Points)
+		}
+	}
+}
+
+// saveGraders stores a user with the GRADER role for each ID, and a taker
+// "taker1" who may not grade.
+func saveGraders(t *testing.T, dbPath string, ids ...string) {
+	t.Helper()
+	users, err := NewUserStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create user store: %v", err)
+	}
+	defer users.Close()
+
+	saved := []*quiz.User{quiz.NewUser("taker1", "taker1@example.com", "Taker", quiz.TAKER)}
+	for _, id := range ids {
+		saved = append(saved, quiz.NewUser(id, id+"@example.com", id, quiz.GRADER))
+	}
+	for _, u := range saved {
+		if err := users.SaveUser(u); err != nil {
+			t.Fatalf("Failed to save user: %v", err)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/BurningIceCube/quizine/pkg/quiz"
@@ -44,11 +46,103 @@ func createTables(db *sql.DB) error {
 		hint TEXT,
 		time_limit INTEGER NOT NULL,
 		options TEXT,
+		config TEXT NOT NULL DEFAULT '',
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS question_blanks (
+		question_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		options TEXT NOT NULL,
+		answer TEXT NOT NULL,
+		case_sensitive BOOLEAN NOT NULL,
+		FOREIGN KEY (question_id) REFERENCES questions(id),
+		PRIMARY KEY (question_id, position)
+	);
+
+	CREATE TABLE IF NOT EXISTS question_hints (
+		question_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		hint TEXT NOT NULL,
+		FOREIGN KEY (question_id) REFERENCES questions(id),
+		PRIMARY KEY (question_id, position)
+	);
+
+	CREATE TABLE IF NOT EXISTS question_tags (
+		question_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		FOREIGN KEY (question_id) REFERENCES questions(id),
+		PRIMARY KEY (question_id, tag)
 	);`
 
-	_, err := db.Exec(createTableSQL)
-	return err
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return err
+	}
+
+	// Databases created before type specific settings were stored
+	return addColumn(db, "questions", "config", "TEXT NOT NULL DEFAULT ''")
+}
+
+// matchingOptions holds the two Matching lists stored in the options column
+type matchingOptions struct {
+	List1Items []string `json:"list1Items"`
+	List2Items []string `json:"list2Items"`
+}
+
+// numericConfig holds the Numeric settings stored in the config column
+type numericConfig struct {
+	Tolerance         float64 `json:"tolerance"`
+	RelativeTolerance float64 `json:"relativeTolerance"`
+	AllowRange        bool    `json:"allowRange"`
+	Min               float64 `json:"min"`
+	Max               float64 `json:"max"`
+	Unit              string  `json:"unit"`
+}
+
+// hotspotConfig holds the Hotspot settings stored in the config column
+type hotspotConfig struct {
+	Image   quiz.AssetRef    `json:"image"`
+	Grading quiz.GradingMode `json:"grading"`
+}
+
+// manualConfig holds the settings of manually graded questions stored in the
+// config column
+type manualConfig struct {
+	Rubric      string `json:"rubric"`
+	MinWords    int    `json:"minWords,omitempty"`
+	MaxWords    int    `json:"maxWords,omitempty"`
+	MaxDuration int64  `json:"maxDuration,omitempty"`
+}
+
+// multiChoiceConfig holds the MultiChoice settings stored in the config column
+type multiChoiceConfig struct {
+	Explanations  []string `json:"explanations"`
+	PinnedOptions []int    `json:"pinnedOptions,omitempty"`
+}
+
+// multiResponseConfig holds the MultiResponse settings stored in the config column
+type multiResponseConfig struct {
+	gradingConfig
+	PinnedOptions []int `json:"pinnedOptions,omitempty"`
+}
+
+// trueFalseConfig holds the TrueFalse settings stored in the config column
+type trueFalseConfig struct {
+	TrueExplanation  string `json:"trueExplanation"`
+	FalseExplanation string `json:"falseExplanation"`
+}
+
+// fillInConfig holds the FillIn settings stored in the config column
+type fillInConfig struct {
+	CorrectAnswers []string `json:"correctAnswers"`
+	Patterns       []string `json:"patterns"`
+	quiz.TextMatching
+}
+
+// gradingConfig holds the grading mode stored in the config column
+type gradingConfig struct {
+	Grading quiz.GradingMode `json:"grading"`
 }
 
 func (qs *QuestionStore) Close() error {
@@ -57,6 +151,7 @@ func (qs *QuestionStore) Close() error {
 
 func (qs *QuestionStore) SaveQuestion(q quiz.Questioner) error {
 	var optionsJSON string
+	var configJSON string
 	var questionType string
 
 	switch q := q.(type) {
@@ -66,18 +161,142 @@ func (qs *QuestionStore) SaveQuestion(q quiz.Questioner) error {
 			return fmt.Errorf("failed to marshal options: %v", err)
 		}
 		optionsJSON = string(optionsJSONBytes)
+		configJSONBytes, err := json.Marshal(multiChoiceConfig{
+			Explanations:  q.Explanations,
+			PinnedOptions: q.PinnedOptions,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
 		questionType = "MULTI_CHOICE"
 	case *quiz.TrueFalse:
+		configJSONBytes, err := json.Marshal(trueFalseConfig{
+			TrueExplanation:  q.TrueExplanation,
+			FalseExplanation: q.FalseExplanation,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
 		questionType = "TRUE_FALSE"
 	case *quiz.FillIn:
+		if err := q.Validate(); err != nil {
+			return fmt.Errorf("failed to validate question: %v", err)
+		}
+		configJSONBytes, err := json.Marshal(fillInConfig{
+			CorrectAnswers: q.CorrectAnswers,
+			Patterns:       q.Patterns,
+			TextMatching:   q.TextMatching,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
 		questionType = "FILL_IN"
+	case *quiz.MultiResponse:
+		optionsJSONBytes, err := json.Marshal(q.Options)
+		if err != nil {
+			return fmt.Errorf("failed to marshal options: %v", err)
+		}
+		optionsJSON = string(optionsJSONBytes)
+		configJSONBytes, err := json.Marshal(multiResponseConfig{
+			gradingConfig: gradingConfig{Grading: q.Grading},
+			PinnedOptions: q.PinnedOptions,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "MULTI_RESPONSE"
+	case *quiz.Matching:
+		optionsJSONBytes, err := json.Marshal(matchingOptions{List1Items: q.List1Items, List2Items: q.List2Items})
+		if err != nil {
+			return fmt.Errorf("failed to marshal options: %v", err)
+		}
+		optionsJSON = string(optionsJSONBytes)
+		configJSONBytes, err := json.Marshal(gradingConfig{Grading: q.Grading})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "MATCHING"
+	case *quiz.Ordering:
+		optionsJSONBytes, err := json.Marshal(q.Items)
+		if err != nil {
+			return fmt.Errorf("failed to marshal options: %v", err)
+		}
+		optionsJSON = string(optionsJSONBytes)
+		configJSONBytes, err := json.Marshal(gradingConfig{Grading: q.Grading})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "ORDERING"
+	case *quiz.Numeric:
+		if err := q.Validate(); err != nil {
+			return fmt.Errorf("failed to validate question: %v", err)
+		}
+		configJSONBytes, err := json.Marshal(numericConfig{
+			Tolerance:         q.Tolerance,
+			RelativeTolerance: q.RelativeTolerance,
+			AllowRange:        q.AllowRange,
+			Min:               q.Min,
+			Max:               q.Max,
+			Unit:              q.Unit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "NUMERIC"
+	case *quiz.Cloze:
+		optionsJSONBytes, err := json.Marshal(q.WordBank)
+		if err != nil {
+			return fmt.Errorf("failed to marshal options: %v", err)
+		}
+		optionsJSON = string(optionsJSONBytes)
+		configJSONBytes, err := json.Marshal(gradingConfig{Grading: q.Grading})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "CLOZE"
+	case *quiz.Hotspot:
+		configJSONBytes, err := json.Marshal(hotspotConfig{Image: q.Image, Grading: q.Grading})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "HOTSPOT"
+	case *quiz.Essay:
+		configJSONBytes, err := json.Marshal(manualConfig{Rubric: q.Rubric, MinWords: q.MinWords, MaxWords: q.MaxWords})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "ESSAY"
+	case *quiz.AudioResponse:
+		configJSONBytes, err := json.Marshal(manualConfig{Rubric: q.Rubric, MaxDuration: q.MaxDuration.Milliseconds()})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "AUDIO"
+	case *quiz.VideoResponse:
+		configJSONBytes, err := json.Marshal(manualConfig{Rubric: q.Rubric, MaxDuration: q.MaxDuration.Milliseconds()})
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %v", err)
+		}
+		configJSON = string(configJSONBytes)
+		questionType = "VIDEO"
 	default:
 		return fmt.Errorf("unknown question type")
 	}
 
 	query := `
-	INSERT INTO questions (id, type, prompt, difficulty, answer, hint, time_limit, options)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	INSERT INTO questions (id, type, prompt, difficulty, answer, hint, time_limit, options, config)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON CONFLICT(id) DO UPDATE SET
 		type = excluded.type,
 		prompt = excluded.prompt,
@@ -85,25 +304,78 @@ func (qs *QuestionStore) SaveQuestion(q quiz.Questioner) error {
 		answer = excluded.answer,
 		hint = excluded.hint,
 		time_limit = excluded.time_limit,
-		options = excluded.options`
+		options = excluded.options,
+		config = excluded.config`
 
-	_, err := qs.db.Exec(query,
+	// The first hint is kept in the hint column and the ones revealed after
+	// it in question_hints
+	var hint string
+	hints := q.GetHints()
+	if len(hints) > 0 {
+		hint = hints[0]
+		hints = hints[1:]
+	}
+
+	tx, err := qs.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query,
 		q.GetID(),
 		questionType,
 		q.GetPrompt(),
 		q.GetDifficulty(),
 		getAnswerString(q),
-		getHint(q),
+		hint,
 		q.GetTimeLimit().Milliseconds(),
 		optionsJSON,
+		configJSON,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Save further hints
+	_, err = tx.Exec("DELETE FROM question_hints WHERE question_id = ?", q.GetID())
+	if err != nil {
+		return fmt.Errorf("failed to clear question hints: %v", err)
+	}
+
+	for i, h := range hints {
+		_, err = tx.Exec("INSERT INTO question_hints (question_id, position, hint) VALUES (?, ?, ?)", q.GetID(), i, h)
+		if err != nil {
+			return fmt.Errorf("failed to save question hint: %v", err)
+		}
+	}
+
+	// Save cloze blanks
+	_, err = tx.Exec("DELETE FROM question_blanks WHERE question_id = ?", q.GetID())
+	if err != nil {
+		return fmt.Errorf("failed to clear question blanks: %v", err)
+	}
+
+	if cloze, ok := q.(*quiz.Cloze); ok {
+		for i, blank := range cloze.Blanks {
+			blankOptions, err := json.Marshal(blank.Options)
+			if err != nil {
+				return fmt.Errorf("failed to marshal blank options: %v", err)
+			}
+			_, err = tx.Exec("INSERT INTO question_blanks (question_id, position, name, options, answer, case_sensitive) VALUES (?, ?, ?, ?, ?, ?)",
+				q.GetID(), i, blank.Name, string(blankOptions), blank.CorrectAnswer, blank.CaseSensitive)
+			if err != nil {
+				return fmt.Errorf("failed to save question blank: %v", err)
+			}
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (qs *QuestionStore) GetQuestion(id string) (quiz.Questioner, error) {
 	query := `
-	SELECT type, prompt, difficulty, answer, hint, time_limit, options
+	SELECT type, prompt, difficulty, answer, hint, time_limit, options, config
 	FROM questions
 	WHERE id = ?`
 
@@ -115,6 +387,7 @@ func (qs *QuestionStore) GetQuestion(id string) (quiz.Questioner, error) {
 		hint         string
 		timeLimit    int64
 		optionsJSON  string
+		configJSON   string
 	)
 
 	err := qs.db.QueryRow(query, id).Scan(
@@ -125,53 +398,342 @@ func (qs *QuestionStore) GetQuestion(id string) (quiz.Questioner, error) {
 		&hint,
 		&timeLimit,
 		&optionsJSON,
+		&configJSON,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get question: %v", err)
 	}
 
+	hints, err := qs.getHints(id)
+	if err != nil {
+		return nil, err
+	}
+
 	switch questionType {
 	case "MULTI_CHOICE":
 		var options []string
 		if err := json.Unmarshal([]byte(optionsJSON), &options); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal options: %v", err)
 		}
+		var config multiChoiceConfig
+		if configJSON != "" {
+			if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+			}
+		}
 		return &quiz.MultiChoice{
-			Id:         id,
-			Prompt:     prompt,
-			Options:    options,
-			Difficulty: difficulty,
-			Answer:     answer,
-			Hint:       hint,
-			TimeLimit:  time.Duration(timeLimit) * time.Millisecond,
+			Id:            id,
+			Prompt:        prompt,
+			Options:       options,
+			PinnedOptions: config.PinnedOptions,
+			Explanations:  config.Explanations,
+			Difficulty:    difficulty,
+			Answer:        answer,
+			Hint:          hint,
+			Hints:         hints,
+			TimeLimit:     time.Duration(timeLimit) * time.Millisecond,
 		}, nil
 	case "TRUE_FALSE":
+		var config trueFalseConfig
+		if configJSON != "" {
+			if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+			}
+		}
 		return &quiz.TrueFalse{
-			Id:         id,
-			Prompt:     prompt,
-			Difficulty: difficulty,
-			Answer:     answer == "true",
-			Hint:       hint,
-			TimeLimit:  time.Duration(timeLimit) * time.Millisecond,
+			Id:               id,
+			Prompt:           prompt,
+			Difficulty:       difficulty,
+			Answer:           answer == "true",
+			TrueExplanation:  config.TrueExplanation,
+			FalseExplanation: config.FalseExplanation,
+			Hint:             hint,
+			Hints:            hints,
+			TimeLimit:        time.Duration(timeLimit) * time.Millisecond,
 		}, nil
 	case "FILL_IN":
+		var config fillInConfig
+		if configJSON != "" {
+			if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+			}
+		}
 		return &quiz.FillIn{
+			Id:             id,
+			Prompt:         prompt,
+			Difficulty:     difficulty,
+			Answer:         answer,
+			CorrectAnswers: config.CorrectAnswers,
+			Patterns:       config.Patterns,
+			TextMatching:   config.TextMatching,
+			Hint:           hint,
+			Hints:          hints,
+			TimeLimit:      time.Duration(timeLimit) * time.Millisecond,
+		}, nil
+	case "MULTI_RESPONSE":
+		var options []string
+		if err := json.Unmarshal([]byte(optionsJSON), &options); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal options: %v", err)
+		}
+		indices, err := quiz.ParseIndices(answer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse answer indices: %v", err)
+		}
+		var config multiResponseConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		return &quiz.MultiResponse{
+			Id:                   id,
+			Prompt:               prompt,
+			Options:              options,
+			PinnedOptions:        config.PinnedOptions,
+			Difficulty:           difficulty,
+			CorrectAnswerIndices: indices,
+			Grading:              config.Grading,
+			Hint:                 hint,
+			Hints:                hints,
+			TimeLimit:            time.Duration(timeLimit) * time.Millisecond,
+		}, nil
+	case "MATCHING":
+		var options matchingOptions
+		if err := json.Unmarshal([]byte(optionsJSON), &options); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal options: %v", err)
+		}
+		pairings, err := quiz.ParsePairings(answer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse answer pairings: %v", err)
+		}
+		var config gradingConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		return &quiz.Matching{
+			Id:              id,
+			Prompt:          prompt,
+			List1Items:      options.List1Items,
+			List2Items:      options.List2Items,
+			CorrectPairings: pairings,
+			Difficulty:      difficulty,
+			Grading:         config.Grading,
+			Hint:            hint,
+			Hints:           hints,
+			TimeLimit:       time.Duration(timeLimit) * time.Millisecond,
+		}, nil
+	case "ORDERING":
+		var items []string
+		if err := json.Unmarshal([]byte(optionsJSON), &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal options: %v", err)
+		}
+		order, err := quiz.ParseIndices(answer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse answer order: %v", err)
+		}
+		var config gradingConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		return &quiz.Ordering{
+			Id:                  id,
+			Prompt:              prompt,
+			Items:               items,
+			CorrectOrderIndices: order,
+			Difficulty:          difficulty,
+			Grading:             config.Grading,
+			Hint:                hint,
+			Hints:               hints,
+			TimeLimit:           time.Duration(timeLimit) * time.Millisecond,
+		}, nil
+	case "NUMERIC":
+		correct, err := strconv.ParseFloat(answer, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse numeric answer: %v", err)
+		}
+		var config numericConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		return &quiz.Numeric{
+			Id:                id,
+			Prompt:            prompt,
+			Difficulty:        difficulty,
+			CorrectAnswer:     correct,
+			Tolerance:         config.Tolerance,
+			RelativeTolerance: config.RelativeTolerance,
+			AllowRange:        config.AllowRange,
+			Min:               config.Min,
+			Max:               config.Max,
+			Unit:              config.Unit,
+			Hint:              hint,
+			Hints:             hints,
+			TimeLimit:         time.Duration(timeLimit) * time.Millisecond,
+		}, nil
+	case "CLOZE":
+		var wordBank []string
+		if err := json.Unmarshal([]byte(optionsJSON), &wordBank); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal options: %v", err)
+		}
+		var config gradingConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		blanks, err := qs.getBlanks(id)
+		if err != nil {
+			return nil, err
+		}
+		return &quiz.Cloze{
+			Id:             id,
+			PromptTemplate: prompt,
+			Blanks:         blanks,
+			WordBank:       wordBank,
+			Difficulty:     difficulty,
+			Grading:        config.Grading,
+			Hint:           hint,
+			Hints:          hints,
+			TimeLimit:      time.Duration(timeLimit) * time.Millisecond,
+		}, nil
+	case "HOTSPOT":
+		var areas []quiz.Area
+		if err := json.Unmarshal([]byte(answer), &areas); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal correct areas: %v", err)
+		}
+		var config hotspotConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		return &quiz.Hotspot{
+			Id:           id,
+			Prompt:       prompt,
+			Image:        config.Image,
+			CorrectAreas: areas,
+			Difficulty:   difficulty,
+			Grading:      config.Grading,
+			Hint:         hint,
+			Hints:        hints,
+			TimeLimit:    time.Duration(timeLimit) * time.Millisecond,
+		}, nil
+	case "ESSAY":
+		var config manualConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		return &quiz.Essay{
 			Id:         id,
 			Prompt:     prompt,
 			Difficulty: difficulty,
-			Answer:     answer,
+			Rubric:     config.Rubric,
+			MinWords:   config.MinWords,
+			MaxWords:   config.MaxWords,
 			Hint:       hint,
+			Hints:      hints,
 			TimeLimit:  time.Duration(timeLimit) * time.Millisecond,
 		}, nil
+	case "AUDIO":
+		var config manualConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		return &quiz.AudioResponse{
+			Id:          id,
+			Prompt:      prompt,
+			Difficulty:  difficulty,
+			Rubric:      config.Rubric,
+			MaxDuration: time.Duration(config.MaxDuration) * time.Millisecond,
+			Hint:        hint,
+			Hints:       hints,
+			TimeLimit:   time.Duration(timeLimit) * time.Millisecond,
+		}, nil
+	case "VIDEO":
+		var config manualConfig
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config: %v", err)
+		}
+		return &quiz.VideoResponse{
+			Id:          id,
+			Prompt:      prompt,
+			Difficulty:  difficulty,
+			Rubric:      config.Rubric,
+			MaxDuration: time.Duration(config.MaxDuration) * time.Millisecond,
+			Hint:        hint,
+			Hints:       hints,
+			TimeLimit:   time.Duration(timeLimit) * time.Millisecond,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown question type: %s", questionType)
 	}
 }
 
+// getHints returns the hints revealed after the first hint of a question.
+func (qs *QuestionStore) getHints(questionID string) ([]string, error) {
+	rows, err := qs.db.Query("SELECT hint FROM question_hints WHERE question_id = ? ORDER BY position", questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question hints: %v", err)
+	}
+	defer rows.Close()
+
+	var hints []string
+	for rows.Next() {
+		var hint string
+		if err := rows.Scan(&hint); err != nil {
+			return nil, fmt.Errorf("failed to scan question hint: %v", err)
+		}
+		hints = append(hints, hint)
+	}
+	return hints, rows.Err()
+}
+
+func (qs *QuestionStore) getBlanks(questionID string) ([]quiz.ClozeBlank, error) {
+	rows, err := qs.db.Query("SELECT name, options, answer, case_sensitive FROM question_blanks WHERE question_id = ? ORDER BY position", questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question blanks: %v", err)
+	}
+	defer rows.Close()
+
+	blanks := make([]quiz.ClozeBlank, 0)
+	for rows.Next() {
+		var (
+			blank       quiz.ClozeBlank
+			optionsJSON string
+		)
+		if err := rows.Scan(&blank.Name, &optionsJSON, &blank.CorrectAnswer, &blank.CaseSensitive); err != nil {
+			return nil, fmt.Errorf("failed to scan question blank: %v", err)
+		}
+		if err := json.Unmarshal([]byte(optionsJSON), &blank.Options); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal blank options: %v", err)
+		}
+		blanks = append(blanks, blank)
+	}
+	return blanks, rows.Err()
+}
+
 func (qs *QuestionStore) DeleteQuestion(id string) error {
-	query := `DELETE FROM questions WHERE id = ?`
-	_, err := qs.db.Exec(query, id)
-	return err
+	tx, err := qs.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM question_blanks WHERE question_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete question blanks: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM question_tags WHERE question_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete question tags: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM question_hints WHERE question_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete question hints: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM questions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete question: %v", err)
+	}
+
+	return tx.Commit()
 }
 
 func (qs *QuestionStore) ListQuestions() ([]quiz.Questioner, error) {
@@ -198,6 +760,104 @@ func (qs *QuestionStore) ListQuestions() ([]quiz.Questioner, error) {
 	return questions, nil
 }
 
+// SetTags replaces the tags of a question.
+func (qs *QuestionStore) SetTags(questionID string, tags ...string) error {
+	tx, err := qs.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM question_tags WHERE question_id = ?", questionID)
+	if err != nil {
+		return fmt.Errorf("failed to clear question tags: %v", err)
+	}
+
+	for _, tag := range tags {
+		_, err = tx.Exec("INSERT OR IGNORE INTO question_tags (question_id, tag) VALUES (?, ?)", questionID, tag)
+		if err != nil {
+			return fmt.Errorf("failed to save question tag: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetTags returns the tags of a question in alphabetical order.
+func (qs *QuestionStore) GetTags(questionID string) ([]string, error) {
+	rows, err := qs.db.Query("SELECT tag FROM question_tags WHERE question_id = ? ORDER BY tag", questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question tags: %v", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// FindQuestions returns the questions matching filter, ordered by ID so the
+// same filter always lists them in the same order.
+func (qs *QuestionStore) FindQuestions(filter quiz.QuestionFilter) ([]quiz.Questioner, error) {
+	query := "SELECT id FROM questions WHERE 1 = 1"
+	var args []any
+
+	if filter.MinDifficulty > 0 {
+		query += " AND difficulty >= ?"
+		args = append(args, filter.MinDifficulty)
+	}
+	if filter.MaxDifficulty > 0 {
+		query += " AND difficulty <= ?"
+		args = append(args, filter.MaxDifficulty)
+	}
+	if len(filter.Types) > 0 {
+		query += " AND type IN (?" + strings.Repeat(", ?", len(filter.Types)-1) + ")"
+		for _, questionType := range filter.Types {
+			args = append(args, questionType)
+		}
+	}
+	for _, tag := range filter.Tags {
+		query += " AND EXISTS (SELECT 1 FROM question_tags t WHERE t.question_id = questions.id AND t.tag = ?)"
+		args = append(args, tag)
+	}
+	query += " ORDER BY id"
+
+	rows, err := qs.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find questions: %v", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan question ID: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find questions: %v", err)
+	}
+
+	questions := make([]quiz.Questioner, 0, len(ids))
+	for _, id := range ids {
+		question, err := qs.GetQuestion(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get question %s: %v", id, err)
+		}
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
 // Helper functions
 func getAnswerString(q quiz.Questioner) string {
 	switch q := q.(type) {
@@ -210,19 +870,20 @@ func getAnswerString(q quiz.Questioner) string {
 		return "false"
 	case *quiz.FillIn:
 		return q.Answer
-	default:
-		return ""
-	}
-}
-
-func getHint(q quiz.Questioner) string {
-	switch q := q.(type) {
-	case *quiz.MultiChoice:
-		return q.Hint
-	case *quiz.TrueFalse:
-		return q.Hint
-	case *quiz.FillIn:
-		return q.Hint
+	case *quiz.MultiResponse:
+		return quiz.EncodeIndices(q.CorrectAnswerIndices)
+	case *quiz.Matching:
+		return quiz.EncodePairings(q.CorrectPairings)
+	case *quiz.Ordering:
+		return quiz.EncodeIndices(q.CorrectOrderIndices)
+	case *quiz.Numeric:
+		return strconv.FormatFloat(q.CorrectAnswer, 'g', -1, 64)
+	case *quiz.Hotspot:
+		areas, err := json.Marshal(q.CorrectAreas)
+		if err != nil {
+			return ""
+		}
+		return string(areas)
 	default:
 		return ""
 	}
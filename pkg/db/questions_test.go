@@ -1,7 +1,9 @@
 package db
 
 import (
+	"database/sql"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -171,3 +173,708 @@ func TestQuestionStore(t *testing.T) {
 		t.Error("Expected error when getting deleted question")
 	}
 }
+
+func TestMultiResponseQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_multi_response.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	mr := &quiz.MultiResponse{
+		Id:                   "mr1",
+		Prompt:               "Which of these are prime?",
+		Options:              []string{"2", "4", "5", "9"},
+		Difficulty:           2,
+		CorrectAnswerIndices: []int{0, 2},
+		Grading:              quiz.PARTIAL_CREDIT,
+		Hint:                 "There are two",
+		TimeLimit:            20 * time.Second,
+	}
+
+	if err := store.SaveQuestion(mr); err != nil {
+		t.Fatalf("Failed to save MultiResponse question: %v", err)
+	}
+
+	retrieved, err := store.GetQuestion("mr1")
+	if err != nil {
+		t.Fatalf("Failed to get MultiResponse question: %v", err)
+	}
+
+	mrQ, ok := retrieved.(*quiz.MultiResponse)
+	if !ok {
+		t.Fatal("Expected MultiResponse type")
+	}
+	if len(mrQ.Options) != 4 {
+		t.Errorf("Expected 4 options, got %d", len(mrQ.Options))
+	}
+	if len(mrQ.CorrectAnswerIndices) != 2 || mrQ.CorrectAnswerIndices[0] != 0 || mrQ.CorrectAnswerIndices[1] != 2 {
+		t.Errorf("Expected correct indices [0 2], got %v", mrQ.CorrectAnswerIndices)
+	}
+	if mrQ.Grading != quiz.PARTIAL_CREDIT {
+		t.Errorf("Expected grading PARTIAL_CREDIT, got %s", mrQ.Grading)
+	}
+	if mrQ.Hint != "There are two" {
+		t.Errorf("Expected hint 'There are two', got '%s'", mrQ.Hint)
+	}
+	if mrQ.TimeLimit != 20*time.Second {
+		t.Errorf("Expected time limit 20s, got %v", mrQ.TimeLimit)
+	}
+	if !mrQ.CheckAnswer("2,0") {
+		t.Error("Expected retrieved question to accept the correct answer")
+	}
+}
+
+func TestChoiceExplanationsQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_explanations.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	mc := &quiz.MultiChoice{
+		Id:           "mc1",
+		Prompt:       "What is 2+2?",
+		Options:      []string{"3", "4"},
+		Explanations: []string{"One too few", "Two pairs make four"},
+		Difficulty:   1,
+		Answer:       "4",
+	}
+	tf := &quiz.TrueFalse{
+		Id:               "tf1",
+		Prompt:           "The sun orbits the earth",
+		Difficulty:       1,
+		Answer:           false,
+		TrueExplanation:  "The earth orbits the sun",
+		FalseExplanation: "Correct",
+	}
+	if err := store.SaveQuestion(mc); err != nil {
+		t.Fatalf("Failed to save MultiChoice question: %v", err)
+	}
+	if err := store.SaveQuestion(tf); err != nil {
+		t.Fatalf("Failed to save TrueFalse question: %v", err)
+	}
+
+	retrieved, err := store.GetQuestion("mc1")
+	if err != nil {
+		t.Fatalf("Failed to get MultiChoice question: %v", err)
+	}
+	mcQ, ok := retrieved.(*quiz.MultiChoice)
+	if !ok {
+		t.Fatal("Expected MultiChoice type")
+	}
+	if len(mcQ.Explanations) != 2 || mcQ.Explanations[1] != "Two pairs make four" {
+		t.Errorf("Expected explanations to round trip, got %v", mcQ.Explanations)
+	}
+
+	retrieved, err = store.GetQuestion("tf1")
+	if err != nil {
+		t.Fatalf("Failed to get TrueFalse question: %v", err)
+	}
+	tfQ, ok := retrieved.(*quiz.TrueFalse)
+	if !ok {
+		t.Fatal("Expected TrueFalse type")
+	}
+	if tfQ.TrueExplanation != "The earth orbits the sun" || tfQ.FalseExplanation != "Correct" {
+		t.Errorf("Expected explanations to round trip, got '%s' and '%s'", tfQ.TrueExplanation, tfQ.FalseExplanation)
+	}
+	if !tfQ.CheckAnswer("false") {
+		t.Error("Expected retrieved question to accept 'false'")
+	}
+}
+
+func TestQuestionStoreMigratesConfigColumn(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_legacy_questions.db"
+	defer os.Remove(dbPath)
+
+	// Create a questions table using the original schema
+	legacy, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = legacy.Exec(`
+	CREATE TABLE questions (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		prompt TEXT NOT NULL,
+		difficulty INTEGER NOT NULL,
+		answer TEXT NOT NULL,
+		hint TEXT,
+		time_limit INTEGER NOT NULL,
+		options TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	INSERT INTO questions (id, type, prompt, difficulty, answer, hint, time_limit, options)
+	VALUES ('fi1', 'FILL_IN', 'The capital of France is ___', 3, 'Paris', '', 45000, '');`)
+	legacy.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	defer store.Close()
+
+	question, err := store.GetQuestion("fi1")
+	if err != nil {
+		t.Fatalf("Failed to get legacy question: %v", err)
+	}
+	if !question.CheckAnswer("Paris") {
+		t.Error("Expected legacy question to accept 'Paris'")
+	}
+	// Legacy rows keep comparing answers exactly
+	if question.CheckAnswer("paris") {
+		t.Error("Expected legacy question to stay case sensitive")
+	}
+}
+
+func TestFillInQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_fill_in.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	fi := &quiz.FillIn{
+		Id:             "fi1",
+		Prompt:         "What is the largest city in Switzerland?",
+		Difficulty:     2,
+		Answer:         "Zürich",
+		CorrectAnswers: []string{"Zurich", "Zuerich"},
+		Patterns:       []string{`Z(ü|u|ue)rich( city)?`},
+		TextMatching: quiz.TextMatching{
+			IgnoreCase:        true,
+			IgnorePunctuation: true,
+			IgnoreDiacritics:  true,
+			MaxEditDistance:   1,
+		},
+	}
+
+	if err := store.SaveQuestion(fi); err != nil {
+		t.Fatalf("Failed to save FillIn question: %v", err)
+	}
+
+	retrieved, err := store.GetQuestion("fi1")
+	if err != nil {
+		t.Fatalf("Failed to get FillIn question: %v", err)
+	}
+
+	fiQ, ok := retrieved.(*quiz.FillIn)
+	if !ok {
+		t.Fatal("Expected FillIn type")
+	}
+	if fiQ.Answer != "Zürich" {
+		t.Errorf("Expected answer 'Zürich', got '%s'", fiQ.Answer)
+	}
+	if len(fiQ.CorrectAnswers) != 2 || fiQ.CorrectAnswers[1] != "Zuerich" {
+		t.Errorf("Expected correct answers [Zurich Zuerich], got %v", fiQ.CorrectAnswers)
+	}
+	if len(fiQ.Patterns) != 1 {
+		t.Errorf("Expected 1 pattern, got %d", len(fiQ.Patterns))
+	}
+	if !fiQ.IgnoreCase || !fiQ.IgnorePunctuation || !fiQ.IgnoreDiacritics || fiQ.MaxEditDistance != 1 {
+		t.Errorf("Unexpected matching rules: %+v", fiQ.TextMatching)
+	}
+	if !fiQ.CheckAnswer("zurick") {
+		t.Error("Expected retrieved question to accept a near miss")
+	}
+	if !fiQ.CheckAnswer("Zuerich City") {
+		t.Error("Expected retrieved question to accept a pattern match")
+	}
+	fi.Patterns = []string{`Z(ü|u`}
+	if err := store.SaveQuestion(fi); err == nil {
+		t.Error("Expected error saving a question with an invalid pattern")
+	}
+}
+
+func TestMatchingQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_matching.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	m := &quiz.Matching{
+		Id:         "m1",
+		Prompt:     "Match each term to its definition",
+		List1Items: []string{"CPU", "RAM"},
+		List2Items: []string{"Volatile memory", "Processor", "Disk"},
+		CorrectPairings: []quiz.Pairing{
+			{List1Index: 0, List2Index: 1},
+			{List1Index: 1, List2Index: 0},
+		},
+		Difficulty: 2,
+		Grading:    quiz.PARTIAL_CREDIT,
+		Hint:       "Disk is not used",
+		TimeLimit:  40 * time.Second,
+	}
+
+	if err := store.SaveQuestion(m); err != nil {
+		t.Fatalf("Failed to save Matching question: %v", err)
+	}
+
+	retrieved, err := store.GetQuestion("m1")
+	if err != nil {
+		t.Fatalf("Failed to get Matching question: %v", err)
+	}
+
+	mQ, ok := retrieved.(*quiz.Matching)
+	if !ok {
+		t.Fatal("Expected Matching type")
+	}
+	if len(mQ.List1Items) != 2 || mQ.List1Items[1] != "RAM" {
+		t.Errorf("Expected List1Items [CPU RAM], got %v", mQ.List1Items)
+	}
+	if len(mQ.List2Items) != 3 || mQ.List2Items[2] != "Disk" {
+		t.Errorf("Expected 3 List2Items ending in Disk, got %v", mQ.List2Items)
+	}
+	if len(mQ.CorrectPairings) != 2 || mQ.CorrectPairings[0] != (quiz.Pairing{List1Index: 0, List2Index: 1}) {
+		t.Errorf("Expected pairings [{0 1} {1 0}], got %v", mQ.CorrectPairings)
+	}
+	if mQ.Grading != quiz.PARTIAL_CREDIT {
+		t.Errorf("Expected grading PARTIAL_CREDIT, got %s", mQ.Grading)
+	}
+	if mQ.Hint != "Disk is not used" {
+		t.Errorf("Expected hint 'Disk is not used', got '%s'", mQ.Hint)
+	}
+	if !mQ.CheckAnswer("0:1,1:0") {
+		t.Error("Expected retrieved question to accept the correct pairings")
+	}
+}
+
+func TestOrderingQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_ordering.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	o := &quiz.Ordering{
+		Id:                  "o1",
+		Prompt:              "Order the build pipeline stages",
+		Items:               []string{"Test", "Compile", "Deploy"},
+		CorrectOrderIndices: []int{1, 0, 2},
+		Difficulty:          2,
+		Grading:             quiz.INVERSION_CREDIT,
+		TimeLimit:           30 * time.Second,
+	}
+
+	if err := store.SaveQuestion(o); err != nil {
+		t.Fatalf("Failed to save Ordering question: %v", err)
+	}
+
+	retrieved, err := store.GetQuestion("o1")
+	if err != nil {
+		t.Fatalf("Failed to get Ordering question: %v", err)
+	}
+
+	oQ, ok := retrieved.(*quiz.Ordering)
+	if !ok {
+		t.Fatal("Expected Ordering type")
+	}
+	if len(oQ.Items) != 3 || oQ.Items[0] != "Test" {
+		t.Errorf("Expected items in canonical order, got %v", oQ.Items)
+	}
+	if quiz.EncodeIndices(oQ.CorrectOrderIndices) != "1,0,2" {
+		t.Errorf("Expected correct order '1,0,2', got %v", oQ.CorrectOrderIndices)
+	}
+	if oQ.Grading != quiz.INVERSION_CREDIT {
+		t.Errorf("Expected grading INVERSION_CREDIT, got %s", oQ.Grading)
+	}
+}
+
+func TestNumericQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_numeric.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	n := &quiz.Numeric{
+		Id:                "n1",
+		Prompt:            "How far does light travel in one second, in km?",
+		Difficulty:        3,
+		CorrectAnswer:     299792.458,
+		Tolerance:         1,
+		RelativeTolerance: 0.01,
+		AllowRange:        true,
+		Min:               299000,
+		Max:               300000,
+		Unit:              "km",
+		Hint:              "About 300 thousand",
+		TimeLimit:         45 * time.Second,
+	}
+
+	if err := store.SaveQuestion(n); err != nil {
+		t.Fatalf("Failed to save Numeric question: %v", err)
+	}
+
+	retrieved, err := store.GetQuestion("n1")
+	if err != nil {
+		t.Fatalf("Failed to get Numeric question: %v", err)
+	}
+
+	nQ, ok := retrieved.(*quiz.Numeric)
+	if !ok {
+		t.Fatal("Expected Numeric type")
+	}
+	if nQ.CorrectAnswer != 299792.458 {
+		t.Errorf("Expected correct answer 299792.458, got %v", nQ.CorrectAnswer)
+	}
+	if nQ.Tolerance != 1 || nQ.RelativeTolerance != 0.01 {
+		t.Errorf("Expected tolerances 1 and 0.01, got %v and %v", nQ.Tolerance, nQ.RelativeTolerance)
+	}
+	if !nQ.AllowRange || nQ.Min != 299000 || nQ.Max != 300000 {
+		t.Errorf("Expected range 299000-300000, got %v %v-%v", nQ.AllowRange, nQ.Min, nQ.Max)
+	}
+	if nQ.Unit != "km" {
+		t.Errorf("Expected unit 'km', got '%s'", nQ.Unit)
+	}
+	if !nQ.CheckAnswer("299792458 m") {
+		t.Error("Expected retrieved question to accept the answer in metres")
+	}
+	n.Min, n.Max = 300000, 299000
+	if err := store.SaveQuestion(n); err == nil {
+		t.Error("Expected error saving a question whose range ends before it starts")
+	}
+}
+
+func TestClozeQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_cloze.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	c := &quiz.Cloze{
+		Id:             "c1",
+		PromptTemplate: "Ich {{verb}} nach {{city}}.",
+		Blanks: []quiz.ClozeBlank{
+			{Name: "verb", Options: []string{"fahre", "fährt"}, CorrectAnswer: "fahre"},
+			{Name: "city", CorrectAnswer: "Berlin", CaseSensitive: true},
+		},
+		WordBank:   []string{"fahre", "fährt", "Berlin"},
+		Difficulty: 2,
+		Grading:    quiz.PARTIAL_CREDIT,
+		Hint:       "First person singular",
+		TimeLimit:  60 * time.Second,
+	}
+
+	if err := store.SaveQuestion(c); err != nil {
+		t.Fatalf("Failed to save Cloze question: %v", err)
+	}
+
+	// Saving again must replace the blanks rather than add to them
+	if err := store.SaveQuestion(c); err != nil {
+		t.Fatalf("Failed to save Cloze question twice: %v", err)
+	}
+
+	retrieved, err := store.GetQuestion("c1")
+	if err != nil {
+		t.Fatalf("Failed to get Cloze question: %v", err)
+	}
+
+	cQ, ok := retrieved.(*quiz.Cloze)
+	if !ok {
+		t.Fatal("Expected Cloze type")
+	}
+	if cQ.PromptTemplate != "Ich {{verb}} nach {{city}}." {
+		t.Errorf("Expected prompt template, got '%s'", cQ.PromptTemplate)
+	}
+	if len(cQ.Blanks) != 2 {
+		t.Fatalf("Expected 2 blanks, got %d", len(cQ.Blanks))
+	}
+	if cQ.Blanks[0].Name != "verb" || len(cQ.Blanks[0].Options) != 2 || cQ.Blanks[0].CorrectAnswer != "fahre" {
+		t.Errorf("Unexpected first blank: %+v", cQ.Blanks[0])
+	}
+	if cQ.Blanks[1].Name != "city" || len(cQ.Blanks[1].Options) != 0 || !cQ.Blanks[1].CaseSensitive {
+		t.Errorf("Unexpected second blank: %+v", cQ.Blanks[1])
+	}
+	if len(cQ.WordBank) != 3 {
+		t.Errorf("Expected 3 word bank items, got %d", len(cQ.WordBank))
+	}
+	if cQ.Grading != quiz.PARTIAL_CREDIT {
+		t.Errorf("Expected grading PARTIAL_CREDIT, got %s", cQ.Grading)
+	}
+	if !cQ.CheckAnswer(quiz.EncodeBlanks(map[string]string{"verb": "fahre", "city": "Berlin"})) {
+		t.Error("Expected retrieved question to accept the correct answer")
+	}
+
+	// Test DeleteQuestion removes the blanks too
+	if err := store.DeleteQuestion("c1"); err != nil {
+		t.Fatalf("Failed to delete Cloze question: %v", err)
+	}
+	blanks, err := store.getBlanks("c1")
+	if err != nil {
+		t.Fatalf("Failed to get blanks: %v", err)
+	}
+	if len(blanks) != 0 {
+		t.Errorf("Expected blanks to be deleted, got %d", len(blanks))
+	}
+}
+
+func TestHotspotQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_hotspot.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	h := &quiz.Hotspot{
+		Id:     "h1",
+		Prompt: "Click on the left ventricle",
+		Image:  "file://anatomy/heart.png",
+		CorrectAreas: []quiz.Area{
+			{Shape: quiz.POLYGON, Points: []quiz.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 10}}},
+			{Shape: quiz.CIRCLE, Points: []quiz.Point{{X: 50, Y: 50}}, Radius: 5},
+		},
+		Difficulty: 3,
+		Grading:    quiz.PARTIAL_CREDIT,
+		Hint:       "Bottom right of the image",
+		TimeLimit:  30 * time.Second,
+	}
+
+	if err := store.SaveQuestion(h); err != nil {
+		t.Fatalf("Failed to save Hotspot question: %v", err)
+	}
+
+	retrieved, err := store.GetQuestion("h1")
+	if err != nil {
+		t.Fatalf("Failed to get Hotspot question: %v", err)
+	}
+
+	hQ, ok := retrieved.(*quiz.Hotspot)
+	if !ok {
+		t.Fatal("Expected Hotspot type")
+	}
+	if hQ.Image != "file://anatomy/heart.png" {
+		t.Errorf("Expected image 'file://anatomy/heart.png', got '%s'", hQ.Image)
+	}
+	if len(hQ.CorrectAreas) != 2 {
+		t.Fatalf("Expected 2 areas, got %d", len(hQ.CorrectAreas))
+	}
+	if hQ.CorrectAreas[0].Shape != quiz.POLYGON || len(hQ.CorrectAreas[0].Points) != 3 {
+		t.Errorf("Unexpected first area: %+v", hQ.CorrectAreas[0])
+	}
+	if hQ.CorrectAreas[1].Shape != quiz.CIRCLE || hQ.CorrectAreas[1].Radius != 5 {
+		t.Errorf("Unexpected second area: %+v", hQ.CorrectAreas[1])
+	}
+	if hQ.Grading != quiz.PARTIAL_CREDIT {
+		t.Errorf("Expected grading PARTIAL_CREDIT, got %s", hQ.Grading)
+	}
+	if !hQ.CheckAnswer("5,2;52,50") {
+		t.Error("Expected retrieved question to accept clicks in both areas")
+	}
+}
+
+func TestManuallyGradedQuestionStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_manual.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	questions := []quiz.Questioner{
+		&quiz.Essay{Id: "e1", Prompt: "Explain recursion", Difficulty: 5, Rubric: "Mentions a base case", MinWords: 50, MaxWords: 200},
+		&quiz.AudioResponse{Id: "a1", Prompt: "Read the passage aloud", Difficulty: 2, MaxDuration: 90 * time.Second},
+		&quiz.VideoResponse{Id: "v1", Prompt: "Show a square knot", Difficulty: 3, MaxDuration: 2 * time.Minute},
+	}
+	for _, q := range questions {
+		if err := store.SaveQuestion(q); err != nil {
+			t.Fatalf("Failed to save question %s: %v", q.GetID(), err)
+		}
+	}
+
+	retrieved, err := store.GetQuestion("e1")
+	if err != nil {
+		t.Fatalf("Failed to get Essay question: %v", err)
+	}
+	if e, ok := retrieved.(*quiz.Essay); !ok {
+		t.Error("Expected Essay type")
+	} else if e.Rubric != "Mentions a base case" || e.MinWords != 50 || e.MaxWords != 200 {
+		t.Errorf("Unexpected essay settings: %+v", e)
+	}
+
+	retrieved, err = store.GetQuestion("a1")
+	if err != nil {
+		t.Fatalf("Failed to get AudioResponse question: %v", err)
+	}
+	if a, ok := retrieved.(*quiz.AudioResponse); !ok {
+		t.Error("Expected AudioResponse type")
+	} else if a.MaxDuration != 90*time.Second {
+		t.Errorf("Expected max duration 90s, got %v", a.MaxDuration)
+	}
+
+	retrieved, err = store.GetQuestion("v1")
+	if err != nil {
+		t.Fatalf("Failed to get VideoResponse question: %v", err)
+	}
+	if v, ok := retrieved.(*quiz.VideoResponse); !ok {
+		t.Error("Expected VideoResponse type")
+	} else if v.MaxDuration != 2*time.Minute || v.Difficulty != 3 {
+		t.Errorf("Unexpected video settings: %+v", v)
+	}
+}
+
+func TestFindQuestions(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_find_questions.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuestionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create question store: %v", err)
+	}
+	defer store.Close()
+
+	questions := []quiz.Questioner{
+		&quiz.TrueFalse{Id: "q1", Prompt: "TCP is connection oriented", Difficulty: 2, Answer: true},
+		&quiz.MultiChoice{Id: "q2", Prompt: "Which layer is IP?", Options: []string{"2", "3"}, Difficulty: 3, Answer: "3"},
+		&quiz.TrueFalse{Id: "q3", Prompt: "UDP retransmits", Difficulty: 1, Answer: false},
+		&quiz.TrueFalse{Id: "q4", Prompt: "Go has generics", Difficulty: 2, Answer: true},
+	}
+	for _, q := range questions {
+		if err := store.SaveQuestion(q); err != nil {
+			t.Fatalf("Failed to save question: %v", err)
+		}
+	}
+	tags := map[string][]string{
+		"q1": {"networking", "tcp"},
+		"q2": {"networking"},
+		"q3": {"networking"},
+		"q4": {"go"},
+	}
+	for id, questionTags := range tags {
+		if err := store.SetTags(id, questionTags...); err != nil {
+			t.Fatalf("Failed to set tags: %v", err)
+		}
+	}
+
+	got, err := store.GetTags("q1")
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(got) != 2 || got[0] != "networking" || got[1] != "tcp" {
+		t.Errorf("Expected tags [networking tcp], got %v", got)
+	}
+
+	tests := []struct {
+		filter quiz.QuestionFilter
+		want   []string
+	}{
+		{quiz.QuestionFilter{}, []string{"q1", "q2", "q3", "q4"}},
+		{quiz.QuestionFilter{Tags: []string{"networking"}}, []string{"q1", "q2", "q3"}},
+		{quiz.QuestionFilter{Tags: []string{"networking", "tcp"}}, []string{"q1"}},
+		{quiz.QuestionFilter{Tags: []string{"networking"}, MinDifficulty: 2, MaxDifficulty: 3}, []string{"q1", "q2"}},
+		{quiz.QuestionFilter{Tags: []string{"networking"}, Types: []string{"TRUE_FALSE"}}, []string{"q1", "q3"}},
+		{quiz.QuestionFilter{MaxDifficulty: 1}, []string{"q3"}},
+	}
+	for _, tt := range tests {
+		found, err := store.FindQuestions(tt.filter)
+		if err != nil {
+			t.Fatalf("Failed to find questions: %v", err)
+		}
+		var ids []string
+		for _, q := range found {
+			ids = append(ids, q.GetID())
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Expected %v for %+v, got %v", tt.want, tt.filter, ids)
+		}
+	}
+
+	// Deleting a question removes its tags
+	if err := store.DeleteQuestion("q1"); err != nil {
+		t.Fatalf("Failed to delete question: %v", err)
+	}
+	got, err = store.GetTags("q1")
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Expected no tags, got %v", got)
+	}
+}
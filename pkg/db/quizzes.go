@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 type QuizStore struct {
 	db            *sql.DB
 	questionStore *QuestionStore
+	clock         quiz.Clock
 }
 
 func NewQuizStore(dbPath string) (*QuizStore, error) {
@@ -38,47 +40,360 @@ func NewQuizStore(dbPath string) (*QuizStore, error) {
 	return &QuizStore{db: db, questionStore: questionStore}, nil
 }
 
-func createQuizTables(db *sql.DB) error {
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS quizzes (
-		id TEXT PRIMARY KEY,
-		status TEXT NOT NULL,
-		current_index INTEGER NOT NULL,
-		score INTEGER NOT NULL,
-		completed BOOLEAN NOT NULL,
-		start_time TIMESTAMP NOT NULL,
-		creation_date TIMESTAMP NOT NULL,
-		time_taken INTEGER NOT NULL,
-		correct_count INTEGER NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
+// quizQuestionsTableSQL creates the table holding the questions of each quiz
+// in the order they are presented. A question may appear more than once.
+// option_order holds the presented order of the question's options, or is
+// empty if they are shown as stored. pool_id names the pool the question was
+// drawn from, or is empty for the definition's own questions. flagged marks
+// questions the user flagged for review and hints_used counts the hints
+// revealed. time_spent is how long, in milliseconds, the question was shown
+// before it was last presented.
+const quizQuestionsTableSQL = `
 	CREATE TABLE IF NOT EXISTS quiz_questions (
 		quiz_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
 		question_id TEXT NOT NULL,
+		option_order TEXT NOT NULL DEFAULT '',
+		pool_id TEXT NOT NULL DEFAULT '',
+		flagged BOOLEAN NOT NULL DEFAULT 0,
+		hints_used INTEGER NOT NULL DEFAULT 0,
+		time_spent INTEGER NOT NULL DEFAULT 0,
 		FOREIGN KEY (quiz_id) REFERENCES quizzes(id),
 		FOREIGN KEY (question_id) REFERENCES questions(id),
-		PRIMARY KEY (quiz_id, question_id)
-	);
+		PRIMARY KEY (quiz_id, position)
+	);`
 
+// quizHistoryTableSQL creates the table holding the answers of each quiz in
+// the order they were submitted. question_index is the position of the
+// answered question in quiz_questions and try counts its answers.
+const quizHistoryTableSQL = `
 	CREATE TABLE IF NOT EXISTS quiz_history (
 		quiz_id TEXT NOT NULL,
+		seq INTEGER NOT NULL,
+		question_index INTEGER NOT NULL,
 		question_id TEXT NOT NULL,
+		try INTEGER NOT NULL DEFAULT 1,
 		correct BOOLEAN NOT NULL,
 		time_taken INTEGER NOT NULL,
+		pending BOOLEAN NOT NULL DEFAULT 0,
+		late BOOLEAN NOT NULL DEFAULT 0,
+		hints_used INTEGER NOT NULL DEFAULT 0,
+		answer TEXT NOT NULL DEFAULT '',
+		score REAL NOT NULL DEFAULT 0,
+		max_score REAL NOT NULL DEFAULT 0,
+		feedback TEXT NOT NULL DEFAULT '',
+		submitted_at TIMESTAMP,
+		FOREIGN KEY (quiz_id) REFERENCES quizzes(id),
+		FOREIGN KEY (question_id) REFERENCES questions(id),
+		PRIMARY KEY (quiz_id, seq)
+	);`
+
+// quizAdaptiveStepsTableSQL creates the table holding how each question of
+// an adaptive quiz was chosen, by its position in quiz_questions.
+const quizAdaptiveStepsTableSQL = `
+	CREATE TABLE IF NOT EXISTS quiz_adaptive_steps (
+		quiz_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		question_id TEXT NOT NULL,
+		target INTEGER NOT NULL,
+		difficulty INTEGER NOT NULL,
+		streak INTEGER NOT NULL,
+		estimate REAL NOT NULL,
+		FOREIGN KEY (quiz_id) REFERENCES quizzes(id),
+		FOREIGN KEY (question_id) REFERENCES questions(id),
+		PRIMARY KEY (quiz_id, position)
+	);`
+
+// quizAdaptiveCandidatesTableSQL creates the table holding the IDs of the
+// questions an adaptive quiz picks from, fixed when it started.
+const quizAdaptiveCandidatesTableSQL = `
+	CREATE TABLE IF NOT EXISTS quiz_adaptive_candidates (
+		quiz_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		question_id TEXT NOT NULL,
 		FOREIGN KEY (quiz_id) REFERENCES quizzes(id),
 		FOREIGN KEY (question_id) REFERENCES questions(id),
-		PRIMARY KEY (quiz_id, question_id)
+		PRIMARY KEY (quiz_id, position)
+	);`
+
+func createQuizTables(db *sql.DB) error {
+	if err := createDefinitionTables(db); err != nil {
+		return err
+	}
+	if err := createUserTables(db); err != nil {
+		return err
+	}
+
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS quizzes (
+		id TEXT PRIMARY KEY,
+		definition_id TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL,
+		current_index INTEGER NOT NULL,
+		score REAL NOT NULL,
+		completed BOOLEAN NOT NULL,
+		start_time TIMESTAMP NOT NULL,
+		creation_date TIMESTAMP NOT NULL,
+		presented_at TIMESTAMP,
+		seed INTEGER NOT NULL DEFAULT 0,
+		scoring_method TEXT NOT NULL DEFAULT '',
+		scoring_config TEXT NOT NULL DEFAULT '',
+		settings TEXT NOT NULL DEFAULT '',
+		paused_at TIMESTAMP,
+		paused_time INTEGER NOT NULL DEFAULT 0,
+		time_taken INTEGER NOT NULL,
+		correct_count INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	);`
 
-	_, err := db.Exec(createTableSQL)
-	return err
+	if _, err := db.Exec(createTableSQL); err != nil {
+		return err
+	}
+	if _, err := db.Exec(quizQuestionsTableSQL); err != nil {
+		return err
+	}
+	if _, err := db.Exec(quizHistoryTableSQL); err != nil {
+		return err
+	}
+	if _, err := db.Exec(quizAdaptiveStepsTableSQL); err != nil {
+		return err
+	}
+	if _, err := db.Exec(quizAdaptiveCandidatesTableSQL); err != nil {
+		return err
+	}
+
+	// Databases created before manually graded responses were stored
+	if err := addColumn(db, "quiz_history", "pending", "BOOLEAN NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quiz_history", "answer", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before grade results were stored
+	if err := addColumn(db, "quiz_history", "score", "REAL NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quiz_history", "max_score", "REAL NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quiz_history", "feedback", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before every submitted answer was stored
+	if err := addColumn(db, "quiz_history", "submitted_at", "TIMESTAMP"); err != nil {
+		return err
+	}
+
+	// Databases created before question order was stored, whose keys allowed
+	// each question only once per quiz
+	err := rebuildTable(db, "quiz_questions", "position", quizQuestionsTableSQL, `
+	INSERT INTO quiz_questions (quiz_id, position, question_id)
+	SELECT quiz_id, ROW_NUMBER() OVER (PARTITION BY quiz_id ORDER BY rowid) - 1, question_id
+	FROM quiz_questions_old`)
+	if err != nil {
+		return err
+	}
+
+	// Databases created before answer options were shuffled
+	if err := addColumn(db, "quiz_questions", "option_order", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before questions were drawn from pools
+	if err := addColumn(db, "quiz_questions", "pool_id", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before questions could be flagged for review
+	if err := addColumn(db, "quiz_questions", "flagged", "BOOLEAN NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Databases created before hint usage was tracked
+	if err := addColumn(db, "quiz_questions", "hints_used", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quiz_history", "hints_used", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	err = rebuildTable(db, "quiz_history", "seq", quizHistoryTableSQL, `
+	INSERT INTO quiz_history (quiz_id, seq, question_index, question_id, correct, time_taken, pending, answer, score, max_score, feedback, submitted_at)
+	SELECT h.quiz_id, h.seq, COALESCE(
+		(SELECT MIN(position) FROM quiz_questions q WHERE q.quiz_id = h.quiz_id AND q.question_id = h.question_id), h.seq),
+		h.question_id, h.correct, h.time_taken, h.pending, h.answer, h.score, h.max_score, h.feedback, h.submitted_at
+	FROM (SELECT *, ROW_NUMBER() OVER (PARTITION BY quiz_id ORDER BY rowid) - 1 AS seq FROM quiz_history_old) h`)
+	if err != nil {
+		return err
+	}
+
+	// Databases created before questions could be retried
+	if err := addColumn(db, "quiz_history", "try", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return err
+	}
+
+	// Databases created before time limits were enforced
+	if err := addColumn(db, "quizzes", "presented_at", "TIMESTAMP"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quiz_history", "late", "BOOLEAN NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Databases created before attempts had a seeded random source
+	if err := addColumn(db, "quizzes", "seed", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Databases created before attempts recorded how they were scored
+	if err := addColumn(db, "quizzes", "scoring_method", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quizzes", "scoring_config", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before attempts kept the settings they started with
+	if err := addColumn(db, "quizzes", "settings", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Databases created before scores could be fractional
+	if err := retypeColumn(db, "quizzes", "score", "REAL NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Databases created before attempts could be paused
+	if err := addColumn(db, "quizzes", "paused_at", "TIMESTAMP"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "quizzes", "paused_time", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Databases created before time on a question added up across visits
+	if err := addColumn(db, "quiz_questions", "time_spent", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	// Databases created before quizzes were split into definitions and attempts
+	if err := addColumn(db, "quizzes", "definition_id", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := migrateLegacyQuizzes(db); err != nil {
+		return fmt.Errorf("failed to migrate quizzes: %v", err)
+	}
+
+	if err := createResultTables(db); err != nil {
+		return err
+	}
+	return createGradingTables(db)
+}
+
+// migrateLegacyQuizzes gives every quiz stored before definitions existed a
+// published implicit definition with the quiz's ID and questions, as
+// quiz.NewQuiz would.
+func migrateLegacyQuizzes(db *sql.DB) error {
+	rows, err := db.Query("SELECT id, creation_date FROM quizzes WHERE definition_id = ''")
+	if err != nil {
+		return err
+	}
+
+	var legacy []*quiz.QuizDefinition
+	for rows.Next() {
+		d := &quiz.QuizDefinition{ScoringMethod: quiz.DIFFICULTY_WEIGHTED, Status: quiz.PUBLISHED}
+		if err := rows.Scan(&d.Id, &d.CreatedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		d.Title = d.Id
+		d.AttemptID = d.Id
+		d.UpdatedAt = d.CreatedAt
+		legacy = append(legacy, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, d := range legacy {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		questionRows, err := tx.Query("SELECT question_id FROM quiz_questions WHERE quiz_id = ? ORDER BY position", d.Id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		d.QuestionIDs = []string{}
+		for questionRows.Next() {
+			var questionID string
+			if err := questionRows.Scan(&questionID); err != nil {
+				questionRows.Close()
+				tx.Rollback()
+				return err
+			}
+			d.QuestionIDs = append(d.QuestionIDs, questionID)
+		}
+		questionRows.Close()
+		if err := questionRows.Err(); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := ensureDefinition(tx, d); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec("UPDATE quizzes SET definition_id = ? WHERE id = ?", d.Id, d.Id); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureDefinition saves d within tx unless a definition with its ID is
+// already stored.
+func ensureDefinition(tx *sql.Tx, d *quiz.QuizDefinition) error {
+	stored, err := definitionStored(tx, d)
+	if err != nil || stored {
+		return err
+	}
+	return saveDefinition(tx, d)
 }
 
 func (qs *QuizStore) Close() error {
 	return qs.db.Close()
 }
 
+// SetClock makes attempts started or loaded by the store read the time from
+// clock instead of the system clock.
+func (qs *QuizStore) SetClock(clock quiz.Clock) {
+	qs.clock = clock
+}
+
+// now returns the time on the store's clock, or the system time if it has
+// none.
+func (qs *QuizStore) now() time.Time {
+	if qs.clock == nil {
+		return time.Now()
+	}
+	return qs.clock.Now()
+}
+
+// options returns the options attempts started or loaded by the store use.
+func (qs *QuizStore) options() quiz.Options {
+	return quiz.Options{Clock: qs.clock}
+}
+
 func (qs *QuizStore) SaveQuiz(q *quiz.Quiz) error {
 	tx, err := qs.db.Begin()
 	if err != nil {
@@ -86,26 +401,61 @@ func (qs *QuizStore) SaveQuiz(q *quiz.Quiz) error {
 	}
 	defer tx.Rollback()
 
+	if err := saveQuiz(tx, q); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// saveQuiz writes q and its questions and history within tx. The attempt's
+// definition is stored too if it is not stored yet.
+func saveQuiz(tx *sql.Tx, q *quiz.Quiz) error {
+	definition := q.GetDefinition()
+	if err := ensureDefinition(tx, definition); err != nil {
+		return err
+	}
+
+	scoringMethod, scoringConfig, err := encodeScoring(q.GetScoring())
+	if err != nil {
+		return err
+	}
+	settings, err := json.Marshal(q.GetSettings())
+	if err != nil {
+		return fmt.Errorf("failed to marshal attempt settings: %v", err)
+	}
+
 	// Save quiz metadata
 	query := `
-	INSERT INTO quizzes (id, status, current_index, score, completed, start_time, creation_date, time_taken, correct_count)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	INSERT INTO quizzes (id, definition_id, status, current_index, score, completed, start_time, creation_date, presented_at, seed, scoring_method, scoring_config, settings, paused_at, paused_time, time_taken, correct_count)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON CONFLICT(id) DO UPDATE SET
 		status = excluded.status,
 		current_index = excluded.current_index,
 		score = excluded.score,
 		completed = excluded.completed,
+		presented_at = excluded.presented_at,
+		paused_at = excluded.paused_at,
+		paused_time = excluded.paused_time,
 		time_taken = excluded.time_taken,
 		correct_count = excluded.correct_count`
 
 	_, err = tx.Exec(query,
 		q.Id,
+		definition.Id,
 		string(q.GetStatus()),
 		q.GetCurrentIndex(),
-		q.GetScore(),
+		q.TotalPoints(),
 		q.IsCompleted(),
 		q.GetStartTime(),
 		q.GetCreationDate(),
+		q.GetPresentedAt(),
+		q.GetSeed(),
+		scoringMethod,
+		scoringConfig,
+		string(settings),
+		sql.NullTime{Time: q.GetPausedAt(), Valid: !q.GetPausedAt().IsZero()},
+		q.GetPausedTime().Milliseconds(),
 		q.GetTimeTaken().Milliseconds(),
 		q.GetCorrectCount(),
 	)
@@ -113,62 +463,157 @@ func (qs *QuizStore) SaveQuiz(q *quiz.Quiz) error {
 		return fmt.Errorf("failed to save quiz: %v", err)
 	}
 
+	// Save the users taking the quiz
+	_, err = tx.Exec("DELETE FROM attempt_users WHERE quiz_id = ?", q.Id)
+	if err != nil {
+		return fmt.Errorf("failed to clear attempt users: %v", err)
+	}
+
+	for _, userID := range q.GetUserIDs() {
+		_, err = tx.Exec("INSERT INTO attempt_users (quiz_id, user_id) VALUES (?, ?)", q.Id, userID)
+		if err != nil {
+			return fmt.Errorf("failed to save attempt user: %v", err)
+		}
+	}
+
 	// Save quiz questions
 	_, err = tx.Exec("DELETE FROM quiz_questions WHERE quiz_id = ?", q.Id)
 	if err != nil {
 		return fmt.Errorf("failed to clear quiz questions: %v", err)
 	}
 
-	for _, question := range q.GetQuestions() {
-		_, err = tx.Exec("INSERT INTO quiz_questions (quiz_id, question_id) VALUES (?, ?)",
-			q.Id, question.GetID())
+	for i, question := range q.GetQuestions() {
+		var optionOrder string
+		if order := q.GetOptionOrder(i); order != nil {
+			optionOrder = quiz.EncodeIndices(order)
+		}
+		_, err = tx.Exec("INSERT INTO quiz_questions (quiz_id, position, question_id, option_order, pool_id, flagged, hints_used, time_spent) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			q.Id, i, question.GetID(), optionOrder, q.GetQuestionPool(i), q.IsFlagged(i), q.GetHintsUsed(i), q.GetTimeSpent(i).Milliseconds())
 		if err != nil {
 			return fmt.Errorf("failed to save quiz question: %v", err)
 		}
 	}
 
+	// Save how an adaptive quiz chose its questions
+	_, err = tx.Exec("DELETE FROM quiz_adaptive_steps WHERE quiz_id = ?", q.Id)
+	if err != nil {
+		return fmt.Errorf("failed to clear adaptive steps: %v", err)
+	}
+
+	for i, step := range q.GetAdaptiveTrace() {
+		_, err = tx.Exec("INSERT INTO quiz_adaptive_steps (quiz_id, position, question_id, target, difficulty, streak, estimate) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			q.Id, i, step.QuestionID, step.Target, step.Difficulty, step.Streak, step.Estimate)
+		if err != nil {
+			return fmt.Errorf("failed to save adaptive step: %v", err)
+		}
+	}
+
+	if err := saveAdaptiveCandidates(tx, q); err != nil {
+		return err
+	}
+
 	// Save quiz history
 	_, err = tx.Exec("DELETE FROM quiz_history WHERE quiz_id = ?", q.Id)
 	if err != nil {
 		return fmt.Errorf("failed to clear quiz history: %v", err)
 	}
 
-	for _, result := range q.GetQuestionHistory() {
-		_, err = tx.Exec("INSERT INTO quiz_history (quiz_id, question_id, correct, time_taken) VALUES (?, ?, ?, ?)",
-			q.Id, result.QuestionID, result.Correct, result.TimeTaken.Milliseconds())
+	for i, result := range q.GetQuestionHistory() {
+		var submittedAt sql.NullTime
+		if !result.Attempt.SubmittedAt.IsZero() {
+			submittedAt = sql.NullTime{Time: result.Attempt.SubmittedAt, Valid: true}
+		}
+		_, err = tx.Exec("INSERT INTO quiz_history (quiz_id, seq, question_index, question_id, try, correct, time_taken, pending, late, hints_used, answer, score, max_score, feedback, submitted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			q.Id, i, result.QuestionIndex, result.QuestionID, result.Try, result.Correct, result.TimeTaken.Milliseconds(), result.Pending, result.Late, result.HintsUsed, result.Attempt.Answer,
+			result.Grade.ScoreAwarded, result.Grade.MaxScore, result.Grade.Feedback, submittedAt)
 		if err != nil {
 			return fmt.Errorf("failed to save quiz history: %v", err)
 		}
 	}
 
-	return tx.Commit()
+	return saveFirstResult(tx, q)
+}
+
+// saveAdaptiveCandidates stores the questions an adaptive quiz picks from
+// unless they are stored already. They are fixed when the quiz starts, so
+// questions added or retagged later do not change it.
+func saveAdaptiveCandidates(tx *sql.Tx, q *quiz.Quiz) error {
+	if !q.IsAdaptive() {
+		return nil
+	}
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM quiz_adaptive_candidates WHERE quiz_id = ?", q.Id).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check adaptive candidates: %v", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	for i, candidate := range q.GetAdaptiveCandidates() {
+		_, err := tx.Exec("INSERT INTO quiz_adaptive_candidates (quiz_id, position, question_id) VALUES (?, ?, ?)", q.Id, i, candidate.GetID())
+		if err != nil {
+			return fmt.Errorf("failed to save adaptive candidate: %v", err)
+		}
+	}
+	return nil
+}
+
+// saveFirstResult stores the result of a finished attempt unless one is
+// stored already. Stored results are only replaced when the attempt is
+// graded again, so later edits to its questions do not change them.
+func saveFirstResult(tx *sql.Tx, q *quiz.Quiz) error {
+	result, err := q.Result()
+	if err == quiz.ErrNoResult {
+		return nil
+	}
+	exists, err := resultExists(tx, q.Id)
+	if err != nil || exists {
+		return err
+	}
+	return saveResult(tx, result)
 }
 
 func (qs *QuizStore) GetQuiz(id string) (*quiz.Quiz, error) {
 	// Get quiz metadata
 	query := `
-	SELECT status, current_index, score, completed, start_time, creation_date, time_taken, correct_count
+	SELECT definition_id, status, current_index, score, completed, start_time, creation_date, presented_at, seed, scoring_method, scoring_config, settings, paused_at, paused_time, time_taken, correct_count
 	FROM quizzes
 	WHERE id = ?`
 
 	var (
-		status       string
-		currentIndex int
-		score        int
-		completed    bool
-		startTime    time.Time
-		creationDate time.Time
-		timeTaken    int64
-		correctCount int
+		definitionID  string
+		status        string
+		currentIndex  int
+		score         float64
+		completed     bool
+		startTime     time.Time
+		creationDate  time.Time
+		presentedAt   sql.NullTime
+		seed          int64
+		scoringMethod string
+		scoringConfig string
+		settings      string
+		pausedAt      sql.NullTime
+		pausedTime    int64
+		timeTaken     int64
+		correctCount  int
 	)
 
 	err := qs.db.QueryRow(query, id).Scan(
+		&definitionID,
 		&status,
 		&currentIndex,
 		&score,
 		&completed,
 		&startTime,
 		&creationDate,
+		&presentedAt,
+		&seed,
+		&scoringMethod,
+		&scoringConfig,
+		&settings,
+		&pausedAt,
+		&pausedTime,
 		&timeTaken,
 		&correctCount,
 	)
@@ -176,19 +621,72 @@ func (qs *QuizStore) GetQuiz(id string) (*quiz.Quiz, error) {
 		return nil, fmt.Errorf("failed to get quiz: %v", err)
 	}
 
+	definition, err := getDefinition(qs.db, definitionID)
+	if err != nil {
+		return nil, err
+	}
+	scoring, err := decodeScoring(scoringMethod, scoringConfig)
+	if err != nil {
+		return nil, err
+	}
+	// Quizzes stored before their settings follow their definition's
+	var attemptSettings *quiz.AttemptSettings
+	if settings != "" {
+		attemptSettings = &quiz.AttemptSettings{}
+		if err := json.Unmarshal([]byte(settings), attemptSettings); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal attempt settings: %v", err)
+		}
+	}
+
 	// Get quiz questions
-	rows, err := qs.db.Query("SELECT question_id FROM quiz_questions WHERE quiz_id = ?", id)
+	rows, err := qs.db.Query("SELECT question_id, option_order, pool_id, flagged, hints_used, time_spent FROM quiz_questions WHERE quiz_id = ? ORDER BY position", id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get quiz questions: %v", err)
 	}
 	defer rows.Close()
 
-	var questions []quiz.Questioner
+	var (
+		questions     []quiz.Questioner
+		questionPools []string
+		flagged       []int
+		hintsUsed     = make(map[int]int)
+		timeSpent     = make(map[int]time.Duration)
+		optionOrders  [][]int
+		drawn         bool
+		shuffled      bool
+	)
 	for rows.Next() {
-		var questionID string
-		if err := rows.Scan(&questionID); err != nil {
+		var (
+			questionID, optionOrder, poolID string
+			isFlagged                       bool
+			used                            int
+			spent                           int64
+		)
+		if err := rows.Scan(&questionID, &optionOrder, &poolID, &isFlagged, &used, &spent); err != nil {
 			return nil, fmt.Errorf("failed to scan question ID: %v", err)
 		}
+		var order []int
+		if optionOrder != "" {
+			order, err = quiz.ParseIndices(optionOrder)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse option order: %v", err)
+			}
+			shuffled = true
+		}
+		optionOrders = append(optionOrders, order)
+		questionPools = append(questionPools, poolID)
+		if isFlagged {
+			flagged = append(flagged, len(questions))
+		}
+		if used > 0 {
+			hintsUsed[len(questions)] = used
+		}
+		if spent > 0 {
+			timeSpent[len(questions)] = time.Duration(spent) * time.Millisecond
+		}
+		if poolID != "" {
+			drawn = true
+		}
 		// You'll need to implement GetQuestion in QuestionStore
 		question, err := qs.questionStore.GetQuestion(questionID)
 		if err != nil {
@@ -196,9 +694,18 @@ func (qs *QuizStore) GetQuiz(id string) (*quiz.Quiz, error) {
 		}
 		questions = append(questions, question)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get quiz questions: %v", err)
+	}
+	if !shuffled {
+		optionOrders = nil
+	}
+	if !drawn {
+		questionPools = nil
+	}
 
 	// Get quiz history
-	historyRows, err := qs.db.Query("SELECT question_id, correct, time_taken FROM quiz_history WHERE quiz_id = ?", id)
+	historyRows, err := qs.db.Query("SELECT question_index, question_id, try, correct, time_taken, pending, late, hints_used, answer, score, max_score, feedback, submitted_at FROM quiz_history WHERE quiz_id = ? ORDER BY seq", id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get quiz history: %v", err)
 	}
@@ -207,33 +714,179 @@ func (qs *QuizStore) GetQuiz(id string) (*quiz.Quiz, error) {
 	var history []quiz.QuestionResult
 	for historyRows.Next() {
 		var (
-			questionID string
-			correct    bool
-			taken      int64
+			index       int
+			questionID  string
+			try         int
+			correct     bool
+			taken       int64
+			pending     bool
+			late        bool
+			hintsUsed   int
+			answer      string
+			awarded     float64
+			maxScore    float64
+			feedback    string
+			submittedAt sql.NullTime
 		)
-		if err := historyRows.Scan(&questionID, &correct, &taken); err != nil {
+		if err := historyRows.Scan(&index, &questionID, &try, &correct, &taken, &pending, &late, &hintsUsed, &answer, &awarded, &maxScore, &feedback, &submittedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan history: %v", err)
 		}
 		history = append(history, quiz.QuestionResult{
-			QuestionID: questionID,
-			Correct:    correct,
-			TimeTaken:  time.Duration(taken) * time.Millisecond,
+			QuestionID:    questionID,
+			QuestionIndex: index,
+			Try:           try,
+			Correct:       correct,
+			TimeTaken:     time.Duration(taken) * time.Millisecond,
+			Pending:       pending,
+			Late:          late,
+			HintsUsed:     hintsUsed,
+			Attempt: quiz.QuestionAttempt{
+				QuestionID:  questionID,
+				Answer:      answer,
+				SubmittedAt: submittedAt.Time,
+				TimeSpent:   time.Duration(taken) * time.Millisecond,
+			},
+			Grade: quiz.GradeResult{
+				IsCorrect:    correct,
+				ScoreAwarded: awarded,
+				MaxScore:     maxScore,
+				Feedback:     feedback,
+				Pending:      pending,
+			},
 		})
 	}
+	if err := historyRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get quiz history: %v", err)
+	}
+
+	// Get how an adaptive quiz chose its questions, and the questions it
+	// picks the rest from
+	var (
+		trace      []quiz.AdaptiveStep
+		candidates []quiz.Questioner
+	)
+	if definition.Adaptive != nil {
+		trace, err = qs.getAdaptiveTrace(id)
+		if err != nil {
+			return nil, err
+		}
+		candidates, err = qs.getAdaptiveCandidates(id)
+		if err != nil {
+			return nil, err
+		}
+		// Quizzes stored before their candidates were saved pick from the
+		// questions matching the policy now
+		if len(candidates) == 0 {
+			candidates, err = qs.questionStore.FindQuestions(definition.Adaptive.QuestionFilter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find adaptive questions: %v", err)
+			}
+		}
+	}
+
+	// Get the users taking the quiz
+	userRows, err := qs.db.Query("SELECT user_id FROM attempt_users WHERE quiz_id = ? ORDER BY rowid", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempt users: %v", err)
+	}
+	defer userRows.Close()
+
+	var userIDs []string
+	for userRows.Next() {
+		var userID string
+		if err := userRows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %v", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := userRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get attempt users: %v", err)
+	}
+
+	return quiz.RestoreQuiz(quiz.AttemptState{
+		Id:            id,
+		Definition:    definition,
+		Scoring:       scoring,
+		Settings:      attemptSettings,
+		UserIDs:       userIDs,
+		Questions:     questions,
+		Status:        quiz.QuizStatus(status),
+		CurrentIndex:  currentIndex,
+		Score:         score,
+		Completed:     completed,
+		StartTime:     startTime,
+		CreationDate:  creationDate,
+		PresentedAt:   presentedAt.Time,
+		QuestionPools: questionPools,
+		Flagged:       flagged,
+		HintsUsed:     hintsUsed,
+		TimeSpent:     timeSpent,
+		OptionOrders:  optionOrders,
+		Candidates:    candidates,
+		AdaptiveTrace: trace,
+		Seed:          seed,
+		PausedAt:      pausedAt.Time,
+		PausedTime:    time.Duration(pausedTime) * time.Millisecond,
+		TimeTaken:     time.Duration(timeTaken) * time.Millisecond,
+		CorrectCount:  correctCount,
+		History:       history,
+	}, qs.options()), nil
+}
+
+// getAdaptiveTrace reads how an adaptive quiz chose its questions.
+func (qs *QuizStore) getAdaptiveTrace(quizID string) ([]quiz.AdaptiveStep, error) {
+	rows, err := qs.db.Query("SELECT question_id, target, difficulty, streak, estimate FROM quiz_adaptive_steps WHERE quiz_id = ? ORDER BY position", quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adaptive steps: %v", err)
+	}
+	defer rows.Close()
+
+	var trace []quiz.AdaptiveStep
+	for rows.Next() {
+		var step quiz.AdaptiveStep
+		if err := rows.Scan(&step.QuestionID, &step.Target, &step.Difficulty, &step.Streak, &step.Estimate); err != nil {
+			return nil, fmt.Errorf("failed to scan adaptive step: %v", err)
+		}
+		trace = append(trace, step)
+	}
+	return trace, rows.Err()
+}
+
+// getAdaptiveCandidates reads the questions an adaptive quiz picks from.
+// Candidates are read as they are stored now, and those deleted since the
+// quiz started are left out.
+func (qs *QuizStore) getAdaptiveCandidates(quizID string) ([]quiz.Questioner, error) {
+	rows, err := qs.db.Query(`
+		SELECT c.question_id FROM quiz_adaptive_candidates c
+		JOIN questions q ON q.id = c.question_id
+		WHERE c.quiz_id = ? ORDER BY c.position`, quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adaptive candidates: %v", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan question ID: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get adaptive candidates: %v", err)
+	}
 
-	return quiz.NewQuizFromDB(
-		id,
-		questions,
-		quiz.QuizStatus(status),
-		currentIndex,
-		score,
-		completed,
-		startTime,
-		creationDate,
-		time.Duration(timeTaken)*time.Millisecond,
-		correctCount,
-		history,
-	), nil
+	candidates := make([]quiz.Questioner, 0, len(ids))
+	for _, id := range ids {
+		question, err := qs.questionStore.GetQuestion(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get question %s: %v", id, err)
+		}
+		candidates = append(candidates, question)
+	}
+	return candidates, nil
 }
 
 func (qs *QuizStore) DeleteQuiz(id string) error {
@@ -243,11 +896,35 @@ func (qs *QuizStore) DeleteQuiz(id string) error {
 	}
 	defer tx.Rollback()
 
+	if err := deleteResult(tx, id); err != nil {
+		return err
+	}
+
 	_, err = tx.Exec("DELETE FROM quiz_history WHERE quiz_id = ?", id)
 	if err != nil {
 		return fmt.Errorf("failed to delete quiz history: %v", err)
 	}
 
+	_, err = tx.Exec("DELETE FROM manual_grades WHERE quiz_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete manual grades: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM attempt_users WHERE quiz_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attempt users: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM quiz_adaptive_steps WHERE quiz_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete adaptive steps: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM quiz_adaptive_candidates WHERE quiz_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete adaptive candidates: %v", err)
+	}
+
 	_, err = tx.Exec("DELETE FROM quiz_questions WHERE quiz_id = ?", id)
 	if err != nil {
 		return fmt.Errorf("failed to delete quiz questions: %v", err)
@@ -258,6 +935,18 @@ func (qs *QuizStore) DeleteQuiz(id string) error {
 		return fmt.Errorf("failed to delete quiz: %v", err)
 	}
 
+	// The implicit definition of an attempt started from questions goes
+	// with it
+	_, err = tx.Exec("DELETE FROM quiz_definition_questions WHERE definition_id IN (SELECT id FROM quiz_definitions WHERE attempt_id = ?)", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete definition questions: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM quiz_definitions WHERE attempt_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete quiz definition: %v", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -284,3 +973,249 @@ func (qs *QuizStore) ListQuizzes() ([]*quiz.Quiz, error) {
 
 	return quizzes, nil
 }
+
+// StartAttempt starts and stores a new attempt of a published definition,
+// taken by the given users. Questions are drawn from the definition's pools,
+// if it has any, and the drawn questions are stored with the attempt.
+// Adaptive attempts pick their first question from the questions matching
+// the definition's adaptive policy.
+func (qs *QuizStore) StartAttempt(definitionID, attemptID string, userIDs ...string) (*quiz.Quiz, error) {
+	definition, err := getDefinition(qs.db, definitionID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make([]quiz.Questioner, 0, len(definition.QuestionIDs))
+	for _, questionID := range definition.QuestionIDs {
+		question, err := qs.questionStore.GetQuestion(questionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get question %s: %v", questionID, err)
+		}
+		questions = append(questions, question)
+	}
+
+	var attempt *quiz.Quiz
+	switch {
+	case definition.Adaptive != nil:
+		var candidates []quiz.Questioner
+		candidates, err = qs.questionStore.FindQuestions(definition.Adaptive.QuestionFilter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find adaptive questions: %v", err)
+		}
+		attempt, err = quiz.NewAdaptiveAttempt(attemptID, definition, candidates, qs.options())
+	case len(definition.Pools) == 0:
+		attempt, err = quiz.NewAttemptWithOptions(attemptID, definition, questions, qs.options())
+	default:
+		candidates := make([][]quiz.Questioner, len(definition.Pools))
+		for i, pool := range definition.Pools {
+			candidates[i], err = qs.questionStore.FindQuestions(pool.QuestionFilter)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find questions for pool %s: %v", pool.Id, err)
+			}
+		}
+		var previous []string
+		if definition.MinimizeOverlap {
+			previous, err = qs.previousQuestionIDs(userIDs)
+			if err != nil {
+				return nil, err
+			}
+		}
+		attempt, err = quiz.NewDrawnAttempt(attemptID, definition, questions, candidates, previous, qs.options())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start attempt: %v", err)
+	}
+	for _, userID := range userIDs {
+		attempt.AddUser(userID)
+	}
+
+	if err := qs.SaveQuiz(attempt); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// previousQuestionIDs returns the questions presented in any stored attempt
+// taken by one of the users.
+func (qs *QuizStore) previousQuestionIDs(userIDs []string) ([]string, error) {
+	var questionIDs []string
+	for _, userID := range userIDs {
+		rows, err := qs.db.Query(`
+		SELECT DISTINCT q.question_id
+		FROM quiz_questions q
+		JOIN attempt_users u ON u.quiz_id = q.quiz_id
+		WHERE u.user_id = ?`, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get previous questions: %v", err)
+		}
+		for rows.Next() {
+			var questionID string
+			if err := rows.Scan(&questionID); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan question ID: %v", err)
+			}
+			questionIDs = append(questionIDs, questionID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to get previous questions: %v", err)
+		}
+	}
+	return questionIDs, nil
+}
+
+// ListAttempts returns every stored attempt of a definition, oldest first.
+func (qs *QuizStore) ListAttempts(definitionID string) ([]*quiz.Quiz, error) {
+	return qs.listAttempts("SELECT id FROM quizzes WHERE definition_id = ? ORDER BY created_at, rowid", definitionID)
+}
+
+// ListUserAttempts returns every stored attempt a user took part in, oldest first.
+func (qs *QuizStore) ListUserAttempts(userID string) ([]*quiz.Quiz, error) {
+	query := `
+	SELECT q.id FROM quizzes q
+	JOIN attempt_users a ON a.quiz_id = q.id
+	WHERE a.user_id = ?
+	ORDER BY q.created_at, q.rowid`
+
+	return qs.listAttempts(query, userID)
+}
+
+// ListDefinitionUsers returns the users who took part in an attempt of a
+// definition, ordered by display name.
+func (qs *QuizStore) ListDefinitionUsers(definitionID string) ([]*quiz.User, error) {
+	query := `
+	SELECT u.id FROM users u
+	JOIN attempt_users a ON a.user_id = u.id
+	JOIN quizzes q ON q.id = a.quiz_id
+	WHERE q.definition_id = ?
+	GROUP BY u.id
+	ORDER BY u.display_name, u.id`
+
+	return listUsers(qs.db, query, definitionID)
+}
+
+// ListPausedAttempts returns the paused attempts a user took part in, or
+// every paused attempt if userID is empty, oldest first.
+func (qs *QuizStore) ListPausedAttempts(userID string) ([]*quiz.Quiz, error) {
+	query := `
+	SELECT q.id FROM quizzes q
+	WHERE q.status = ?
+	AND (? = '' OR EXISTS (SELECT 1 FROM attempt_users a WHERE a.quiz_id = q.id AND a.user_id = ?))
+	ORDER BY q.created_at, q.rowid`
+
+	return qs.listAttempts(query, string(quiz.SAVED), userID, userID)
+}
+
+// ResumeAttempt loads a paused attempt, resumes it and stores it again.
+func (qs *QuizStore) ResumeAttempt(id string) (*quiz.Quiz, error) {
+	attempt, err := qs.GetQuiz(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := attempt.Resume(); err != nil {
+		return nil, fmt.Errorf("failed to resume attempt: %w", err)
+	}
+	if err := qs.SaveQuiz(attempt); err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// listAttempts reads the attempts whose IDs are selected by query.
+func (qs *QuizStore) listAttempts(query string, args ...any) ([]*quiz.Quiz, error) {
+	rows, err := qs.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attempts: %v", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan quiz ID: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list attempts: %v", err)
+	}
+
+	var attempts []*quiz.Quiz
+	for _, id := range ids {
+		attempt, err := qs.GetQuiz(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get quiz %s: %v", id, err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
+
+// RegradeAttempt grades the stored answers of an attempt again against the
+// stored questions and saves the new grades and result. It returns the
+// number of results that changed.
+func (qs *QuizStore) RegradeAttempt(quizID string) (int, error) {
+	q, err := qs.GetQuiz(quizID)
+	if err != nil {
+		return 0, err
+	}
+
+	changed := q.Regrade()
+	if changed == 0 {
+		return 0, nil
+	}
+
+	tx, err := qs.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteResult(tx, quizID); err != nil {
+		return 0, err
+	}
+	if err := saveQuiz(tx, q); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// RegradeQuestion regrades every attempt that answered a question, for
+// example after its answer key was fixed. It returns the number of results
+// that changed.
+func (qs *QuizStore) RegradeQuestion(questionID string) (int, error) {
+	rows, err := qs.db.Query("SELECT DISTINCT quiz_id FROM quiz_history WHERE question_id = ?", questionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find answered quizzes: %v", err)
+	}
+
+	var quizIDs []string
+	for rows.Next() {
+		var quizID string
+		if err := rows.Scan(&quizID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan quiz ID: %v", err)
+		}
+		quizIDs = append(quizIDs, quizID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to find answered quizzes: %v", err)
+	}
+
+	total := 0
+	for _, quizID := range quizIDs {
+		changed, err := qs.RegradeAttempt(quizID)
+		if err != nil {
+			return total, fmt.Errorf("failed to regrade quiz %s: %v", quizID, err)
+		}
+		total += changed
+	}
+	return total, nil
+}
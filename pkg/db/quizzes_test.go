@@ -1,7 +1,11 @@
 package db
 
 import (
+	"database/sql"
+	"errors"
 	"os"
+	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
@@ -76,7 +80,7 @@ func TestQuizStore(t *testing.T) {
 		t.Errorf("Expected current index 0, got %d", retrievedQuiz.GetCurrentIndex())
 	}
 	if retrievedQuiz.GetScore() != 0 {
-		t.Errorf("Expected score 0, got %d", retrievedQuiz.GetScore())
+		t.Errorf("Expected score 0, got %v", retrievedQuiz.GetScore())
 	}
 	if retrievedQuiz.IsCompleted() {
 		t.Error("Expected quiz not completed")
@@ -142,3 +146,1608 @@ func TestQuizStore(t *testing.T) {
 		t.Error("Expected second answer to be incorrect")
 	}
 }
+
+func TestQuizStoreMatchingRoundTrip(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_quiz_matching.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	defer store.Close()
+
+	m := &quiz.Matching{
+		Id:         "m1",
+		Prompt:     "Match each word to its translation",
+		List1Items: []string{"chat", "chien"},
+		List2Items: []string{"dog", "cat"},
+		CorrectPairings: []quiz.Pairing{
+			{List1Index: 0, List2Index: 1},
+			{List1Index: 1, List2Index: 0},
+		},
+		Difficulty: 2,
+	}
+	if err := store.questionStore.SaveQuestion(m); err != nil {
+		t.Fatalf("Failed to save question: %v", err)
+	}
+
+	q := quiz.NewQuiz("quiz1", []quiz.Questioner{m})
+	q.SubmitAnswer("0:1,1:0")
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	retrieved, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if len(retrieved.GetQuestions()) != 1 {
+		t.Fatalf("Expected 1 question, got %d", len(retrieved.GetQuestions()))
+	}
+	mQ, ok := retrieved.GetQuestions()[0].(*quiz.Matching)
+	if !ok {
+		t.Fatal("Expected Matching type")
+	}
+	if !mQ.CheckAnswer("0:1,1:0") {
+		t.Error("Expected restored question to accept the correct pairings")
+	}
+	if retrieved.GetScore() != 2 {
+		t.Errorf("Expected score 2, got %v", retrieved.GetScore())
+	}
+}
+
+func TestQuizStoreGradeResults(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_quiz_grades.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	defer store.Close()
+
+	mr := &quiz.MultiResponse{
+		Id:                   "mr1",
+		Prompt:               "Which are prime?",
+		Options:              []string{"2", "4", "5", "9"},
+		CorrectAnswerIndices: []int{0, 2},
+		Difficulty:           4,
+		Grading:              quiz.PARTIAL_CREDIT,
+	}
+	if err := store.questionStore.SaveQuestion(mr); err != nil {
+		t.Fatalf("Failed to save question: %v", err)
+	}
+
+	q := quiz.NewQuiz("quiz1", []quiz.Questioner{mr})
+	if _, err := q.Submit(quiz.IndicesAnswer{0, 1, 2}); err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	retrieved, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if retrieved.GetScore() != 2 {
+		t.Errorf("Expected score 2, got %v", retrieved.GetScore())
+	}
+
+	grade := retrieved.GetQuestionHistory()[0].Grade
+	if grade.IsCorrect || grade.ScoreAwarded != 2 || grade.MaxScore != 4 {
+		t.Errorf("Unexpected restored grade: %+v", grade)
+	}
+	if grade.Feedback != q.GetQuestionHistory()[0].Grade.Feedback {
+		t.Errorf("Expected feedback '%s', got '%s'", q.GetQuestionHistory()[0].Grade.Feedback, grade.Feedback)
+	}
+}
+
+func TestQuizStoreStartAttempt(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_attempts.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	defer store.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	questions := []quiz.Questioner{
+		&quiz.MultiChoice{Id: "q1", Prompt: "What is 2+2?", Options: []string{"3", "4"}, Difficulty: 1, Answer: "4"},
+		&quiz.TrueFalse{Id: "q2", Prompt: "The sky is blue", Difficulty: 2, Answer: true},
+	}
+	for _, q := range questions {
+		if err := store.questionStore.SaveQuestion(q); err != nil {
+			t.Fatalf("Failed to save question: %v", err)
+		}
+	}
+
+	d := quiz.NewQuizDefinition("def1", "Basics", []string{"q2", "q1"})
+	d.PassingScore = 50
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	if _, err := store.StartAttempt("def1", "a1"); err == nil {
+		t.Error("Expected an attempt of a draft definition to be rejected")
+	}
+
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	first, err := store.StartAttempt("def1", "a1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	if first.CurrentQuestion().GetID() != "q2" {
+		t.Errorf("Expected the attempt to start with q2, got %s", first.CurrentQuestion().GetID())
+	}
+	first.SubmitAnswer("true")
+	if err := store.SaveQuiz(first); err != nil {
+		t.Fatalf("Failed to save attempt: %v", err)
+	}
+	if _, err := store.StartAttempt("def1", "a2"); err != nil {
+		t.Fatalf("Failed to start second attempt: %v", err)
+	}
+
+	attempts, err := store.ListAttempts("def1")
+	if err != nil {
+		t.Fatalf("Failed to list attempts: %v", err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", len(attempts))
+	}
+	if attempts[0].GetScore() != 2 || attempts[1].GetScore() != 0 {
+		t.Errorf("Expected scores 2 and 0, got %v and %v", attempts[0].GetScore(), attempts[1].GetScore())
+	}
+	if attempts[0].GetDefinition().PassingScore != 50 {
+		t.Errorf("Expected the attempt to carry the definition's passing score, got %v", attempts[0].GetDefinition().PassingScore)
+	}
+
+	// Saving an attempt does not overwrite its stored definition
+	d.Title = "Changed"
+	if err := store.SaveQuiz(first); err != nil {
+		t.Fatalf("Failed to save attempt: %v", err)
+	}
+	stored, err := definitions.GetDefinition("def1")
+	if err != nil {
+		t.Fatalf("Failed to get definition: %v", err)
+	}
+	if stored.Title != "Basics" {
+		t.Errorf("Expected title 'Basics', got '%s'", stored.Title)
+	}
+}
+
+func TestQuizStoreAdHocDefinitions(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_adhoc_definitions.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	defer store.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	questions := []quiz.Questioner{
+		&quiz.MultiChoice{Id: "q1", Prompt: "What is 2+2?", Options: []string{"3", "4"}, Difficulty: 1, Answer: "4"},
+		&quiz.TrueFalse{Id: "q2", Prompt: "The sky is blue", Difficulty: 2, Answer: true},
+	}
+	for _, q := range questions {
+		if err := store.questionStore.SaveQuestion(q); err != nil {
+			t.Fatalf("Failed to save question: %v", err)
+		}
+	}
+
+	// A stored definition sharing the attempt's ID is not used by it
+	d := quiz.NewQuizDefinition("quiz1", "Sky", []string{"q2"})
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	if err := store.SaveQuiz(quiz.NewQuiz("quiz1", questions)); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+	retrieved, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if retrieved.AmountOfQuestions() != 2 || retrieved.CurrentQuestion().GetID() != "q1" {
+		t.Errorf("Expected the attempt to keep its own questions, got %d starting with %s",
+			retrieved.AmountOfQuestions(), retrieved.CurrentQuestion().GetID())
+	}
+	if !retrieved.GetDefinition().IsAdHoc() {
+		t.Error("Expected the attempt to keep its implicit definition")
+	}
+
+	// Implicit definitions are not listed
+	listed, err := definitions.ListDefinitions("")
+	if err != nil {
+		t.Fatalf("Failed to list definitions: %v", err)
+	}
+	if len(listed) != 1 || listed[0].Id != "quiz1" || listed[0].IsAdHoc() {
+		t.Errorf("Expected only definition 'quiz1' to be listed, got %v", listed)
+	}
+
+	// Nor can they be overwritten by a definition saved under their ID
+	taken := quiz.NewQuizDefinition(retrieved.GetDefinition().Id, "Taken", []string{"q1"})
+	if err := definitions.SaveDefinition(taken); err == nil {
+		t.Error("Expected saving a definition under an implicit definition's ID to fail")
+	}
+
+	// Deleting the attempt deletes its implicit definition, but not others
+	if err := store.DeleteQuiz("quiz1"); err != nil {
+		t.Fatalf("Failed to delete quiz: %v", err)
+	}
+	if _, err := definitions.GetDefinition(retrieved.GetDefinition().Id); err == nil {
+		t.Error("Expected the implicit definition to be deleted with its attempt")
+	}
+	if _, err := definitions.GetDefinition("quiz1"); err != nil {
+		t.Errorf("Expected definition 'quiz1' to be kept, got %v", err)
+	}
+}
+
+func TestQuizStoreMigratesLegacyQuizzes(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_legacy_quizzes.db"
+	defer os.Remove(dbPath)
+
+	// Create the quiz tables using the original schema
+	legacy, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = legacy.Exec(`
+	CREATE TABLE questions (
+		id TEXT PRIMARY KEY,
+		type TEXT NOT NULL,
+		prompt TEXT NOT NULL,
+		difficulty INTEGER NOT NULL,
+		answer TEXT NOT NULL,
+		hint TEXT,
+		time_limit INTEGER NOT NULL,
+		options TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE quizzes (
+		id TEXT PRIMARY KEY,
+		status TEXT NOT NULL,
+		current_index INTEGER NOT NULL,
+		score INTEGER NOT NULL,
+		completed BOOLEAN NOT NULL,
+		start_time TIMESTAMP NOT NULL,
+		creation_date TIMESTAMP NOT NULL,
+		time_taken INTEGER NOT NULL,
+		correct_count INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE TABLE quiz_questions (
+		quiz_id TEXT NOT NULL,
+		question_id TEXT NOT NULL,
+		PRIMARY KEY (quiz_id, question_id)
+	);
+	CREATE TABLE quiz_history (
+		quiz_id TEXT NOT NULL,
+		question_id TEXT NOT NULL,
+		correct BOOLEAN NOT NULL,
+		time_taken INTEGER NOT NULL,
+		PRIMARY KEY (quiz_id, question_id)
+	);
+	INSERT INTO questions (id, type, prompt, difficulty, answer, hint, time_limit, options)
+	VALUES ('tf1', 'TRUE_FALSE', 'The sky is blue', 2, 'true', '', 15000, '');
+	INSERT INTO quizzes (id, status, current_index, score, completed, start_time, creation_date, time_taken, correct_count)
+	VALUES ('quiz1', 'FINISHED', 0, 2, 1, '2024-01-01 10:00:00', '2024-01-01 10:00:00', 5000, 1);
+	INSERT INTO quiz_questions (quiz_id, question_id) VALUES ('quiz1', 'tf1');
+	INSERT INTO quiz_history (quiz_id, question_id, correct, time_taken) VALUES ('quiz1', 'tf1', 1, 5000);`)
+	legacy.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	defer store.Close()
+
+	q, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get legacy quiz: %v", err)
+	}
+	if q.GetScore() != 2 || !q.IsCompleted() {
+		t.Errorf("Expected completed quiz with score 2, got %v", q.GetScore())
+	}
+	if scoreType, _, err := columnType(store.db, "quizzes", "score"); err != nil || scoreType != "REAL" {
+		t.Errorf("Expected the score column to be REAL, got %q, %v", scoreType, err)
+	}
+
+	d := q.GetDefinition()
+	if d.Id != "quiz1" || !d.IsAdHoc() || d.Status != quiz.PUBLISHED {
+		t.Errorf("Expected published implicit definition 'quiz1', got '%s' of '%s' with status %s", d.Id, d.AttemptID, d.Status)
+	}
+	if len(d.QuestionIDs) != 1 || d.QuestionIDs[0] != "tf1" {
+		t.Errorf("Expected question IDs [tf1], got %v", d.QuestionIDs)
+	}
+
+	attempts, err := store.ListAttempts("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to list attempts: %v", err)
+	}
+	if len(attempts) != 1 {
+		t.Errorf("Expected 1 attempt, got %d", len(attempts))
+	}
+}
+
+func TestQuizStoreRegrade(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_regrade.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	defer store.Close()
+
+	mc := &quiz.MultiChoice{Id: "q1", Prompt: "What is 2+2?", Options: []string{"3", "4", "5"}, Difficulty: 2, Answer: "5"}
+	if err := store.questionStore.SaveQuestion(mc); err != nil {
+		t.Fatalf("Failed to save question: %v", err)
+	}
+
+	// Two takers answer while the answer key is wrong
+	for id, answer := range map[string]string{"quiz1": "4", "quiz2": "5"} {
+		q := quiz.NewQuiz(id, []quiz.Questioner{mc})
+		q.SubmitAnswer(answer)
+		q.NextQuestion()
+		if err := store.SaveQuiz(q); err != nil {
+			t.Fatalf("Failed to save quiz: %v", err)
+		}
+	}
+
+	retrieved, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	attempt := retrieved.GetQuestionHistory()[0].Attempt
+	if attempt.Answer != "4" {
+		t.Errorf("Expected stored answer '4', got '%s'", attempt.Answer)
+	}
+	if attempt.SubmittedAt.IsZero() {
+		t.Error("Expected the submission time to be stored")
+	}
+
+	// Fix the answer key and regrade everyone who answered the question
+	mc.Answer = "4"
+	if err := store.questionStore.SaveQuestion(mc); err != nil {
+		t.Fatalf("Failed to save question: %v", err)
+	}
+	changed, err := store.RegradeQuestion("q1")
+	if err != nil {
+		t.Fatalf("Failed to regrade question: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("Expected 2 changed results, got %d", changed)
+	}
+
+	first, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if first.GetScore() != 2 || !first.GetQuestionHistory()[0].Correct {
+		t.Errorf("Expected quiz1 to score 2 after regrading, got %v", first.GetScore())
+	}
+	second, err := store.GetQuiz("quiz2")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if second.GetScore() != 0 || second.GetCorrectCount() != 0 {
+		t.Errorf("Expected quiz2 to score 0 after regrading, got %v", second.GetScore())
+	}
+
+	changed, err = store.RegradeAttempt("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to regrade attempt: %v", err)
+	}
+	if changed != 0 {
+		t.Errorf("Expected no further changes, got %d", changed)
+	}
+}
+
+// resumeTestQuestions returns questions whose IDs do not sort in the order
+// they are asked, so a restored quiz only keeps its order if it was stored.
+func resumeTestQuestions() []quiz.Questioner {
+	return []quiz.Questioner{
+		&quiz.FillIn{Id: "q3", Prompt: "The capital of France is ___", Difficulty: 3, Answer: "Paris"},
+		&quiz.MultiChoice{Id: "q1", Prompt: "What is 2+2?", Options: []string{"3", "4"}, Difficulty: 1, Answer: "4"},
+		&quiz.TrueFalse{Id: "q4", Prompt: "The sky is green", Difficulty: 2, Answer: false},
+		&quiz.Numeric{Id: "q2", Prompt: "How many legs does a spider have?", Difficulty: 2, CorrectAnswer: 8},
+	}
+}
+
+func openResumeStore(t *testing.T, dbPath string, questions []quiz.Questioner) *QuizStore {
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	for _, q := range questions {
+		if err := store.questionStore.SaveQuestion(q); err != nil {
+			t.Fatalf("Failed to save question: %v", err)
+		}
+	}
+	return store
+}
+
+func questionIDs(q *quiz.Quiz) []string {
+	ids := make([]string, 0, q.AmountOfQuestions())
+	for _, question := range q.GetQuestions() {
+		ids = append(ids, question.GetID())
+	}
+	return ids
+}
+
+func TestQuizStorePreservesQuestionOrder(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_question_order.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	// The same questions in a different order for a second attempt
+	shuffled := []quiz.Questioner{questions[2], questions[0], questions[3], questions[1]}
+	for id, qs := range map[string][]quiz.Questioner{"quiz1": questions, "quiz2": shuffled} {
+		if err := store.SaveQuiz(quiz.NewQuiz(id, qs)); err != nil {
+			t.Fatalf("Failed to save quiz: %v", err)
+		}
+	}
+
+	for id, expected := range map[string][]string{
+		"quiz1": {"q3", "q1", "q4", "q2"},
+		"quiz2": {"q4", "q3", "q2", "q1"},
+	} {
+		retrieved, err := store.GetQuiz(id)
+		if err != nil {
+			t.Fatalf("Failed to get quiz: %v", err)
+		}
+		ids := questionIDs(retrieved)
+		if len(ids) != len(expected) {
+			t.Fatalf("Expected %d questions, got %d", len(expected), len(ids))
+		}
+		for i := range expected {
+			if ids[i] != expected[i] {
+				t.Errorf("Expected %s to be asked in order %v, got %v", id, expected, ids)
+				break
+			}
+		}
+	}
+}
+
+func TestQuizStoreDuplicateQuestions(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_duplicate_questions.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	// Ask the first question again at the end
+	q := quiz.NewQuiz("quiz1", []quiz.Questioner{questions[0], questions[1], questions[0]})
+	q.SubmitAnswer("London")
+	q.NextQuestion()
+	q.SubmitAnswer("4")
+	q.NextQuestion()
+	q.SubmitAnswer("Paris")
+	q.NextQuestion()
+
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz with a repeated question: %v", err)
+	}
+
+	retrieved, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	ids := questionIDs(retrieved)
+	if len(ids) != 3 || ids[0] != "q3" || ids[2] != "q3" {
+		t.Errorf("Expected questions [q3 q1 q3], got %v", ids)
+	}
+
+	history := retrieved.GetQuestionHistory()
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 history entries, got %d", len(history))
+	}
+	if history[0].Correct || !history[2].Correct {
+		t.Error("Expected the repeated question to be wrong first and right second")
+	}
+	if history[0].QuestionIndex != 0 || history[2].QuestionIndex != 2 {
+		t.Errorf("Expected question indices 0 and 2, got %d and %d", history[0].QuestionIndex, history[2].QuestionIndex)
+	}
+	if retrieved.GetScore() != 4 {
+		t.Errorf("Expected score 4, got %v", retrieved.GetScore())
+	}
+}
+
+func TestQuizStoreResumeFidelity(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_resume.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	answers := []string{"Paris", "3", "false", "8"}
+
+	// Answer every question without saving, for reference
+	reference := quiz.NewQuiz("reference", questions)
+	for _, answer := range answers {
+		reference.SubmitAnswer(answer)
+		reference.NextQuestion()
+	}
+
+	// Answer half, save, reopen the database and answer the rest
+	store := openResumeStore(t, dbPath, questions)
+	q := quiz.NewQuiz("quiz1", questions)
+	for _, answer := range answers[:2] {
+		q.SubmitAnswer(answer)
+		q.NextQuestion()
+	}
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+	store.Close()
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen quiz store: %v", err)
+	}
+	defer store.Close()
+
+	resumed, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if resumed.GetCurrentIndex() != 2 {
+		t.Errorf("Expected to resume at index 2, got %d", resumed.GetCurrentIndex())
+	}
+	if resumed.CurrentQuestion().GetID() != "q4" {
+		t.Errorf("Expected to resume at question q4, got %s", resumed.CurrentQuestion().GetID())
+	}
+	if resumed.GetStatus() != q.GetStatus() {
+		t.Errorf("Expected status %s, got %s", q.GetStatus(), resumed.GetStatus())
+	}
+
+	for _, answer := range answers[2:] {
+		resumed.SubmitAnswer(answer)
+		resumed.NextQuestion()
+	}
+	if err := store.SaveQuiz(resumed); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+	finished, err := store.GetQuiz("quiz1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+
+	if !finished.IsCompleted() || finished.GetStatus() != quiz.FINISHED {
+		t.Errorf("Expected finished quiz, got status %s", finished.GetStatus())
+	}
+	if finished.GetScore() != reference.GetScore() {
+		t.Errorf("Expected score %v, got %v", reference.GetScore(), finished.GetScore())
+	}
+	if finished.GetCorrectCount() != reference.GetCorrectCount() {
+		t.Errorf("Expected %d correct answers, got %d", reference.GetCorrectCount(), finished.GetCorrectCount())
+	}
+
+	expected := reference.GetQuestionHistory()
+	history := finished.GetQuestionHistory()
+	if len(history) != len(expected) {
+		t.Fatalf("Expected %d history entries, got %d", len(expected), len(history))
+	}
+	for i := range expected {
+		if history[i].QuestionID != expected[i].QuestionID || history[i].QuestionIndex != expected[i].QuestionIndex {
+			t.Errorf("Entry %d: expected question %s at %d, got %s at %d", i,
+				expected[i].QuestionID, expected[i].QuestionIndex, history[i].QuestionID, history[i].QuestionIndex)
+		}
+		if history[i].Correct != expected[i].Correct || history[i].Attempt.Answer != expected[i].Attempt.Answer {
+			t.Errorf("Entry %d: expected %q graded %v, got %q graded %v", i,
+				expected[i].Attempt.Answer, expected[i].Correct, history[i].Attempt.Answer, history[i].Correct)
+		}
+	}
+}
+
+func TestQuizStoreMigratesQuestionOrder(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_legacy_order.db"
+	defer os.Remove(dbPath)
+
+	// Create quiz tables without positions, as stored before order was kept
+	legacy, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = legacy.Exec(`
+	CREATE TABLE quiz_questions (
+		quiz_id TEXT NOT NULL,
+		question_id TEXT NOT NULL,
+		PRIMARY KEY (quiz_id, question_id)
+	);
+	CREATE TABLE quiz_history (
+		quiz_id TEXT NOT NULL,
+		question_id TEXT NOT NULL,
+		correct BOOLEAN NOT NULL,
+		time_taken INTEGER NOT NULL,
+		PRIMARY KEY (quiz_id, question_id)
+	);
+	INSERT INTO quiz_questions (quiz_id, question_id) VALUES ('quiz1', 'q3'), ('quiz1', 'q1');
+	INSERT INTO quiz_history (quiz_id, question_id, correct, time_taken) VALUES ('quiz1', 'q3', 1, 1000), ('quiz1', 'q1', 0, 2000);`)
+	legacy.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	rows, err := store.db.Query("SELECT position, question_id FROM quiz_questions WHERE quiz_id = 'quiz1' ORDER BY position")
+	if err != nil {
+		t.Fatalf("Failed to read quiz questions: %v", err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var (
+			position   int
+			questionID string
+		)
+		if err := rows.Scan(&position, &questionID); err != nil {
+			t.Fatalf("Failed to scan quiz question: %v", err)
+		}
+		ids = append(ids, questionID)
+	}
+	if len(ids) != 2 || ids[0] != "q3" || ids[1] != "q1" {
+		t.Errorf("Expected migrated order [q3 q1], got %v", ids)
+	}
+
+	var index int
+	if err := store.db.QueryRow("SELECT question_index FROM quiz_history WHERE quiz_id = 'quiz1' AND question_id = 'q1'").Scan(&index); err != nil {
+		t.Fatalf("Failed to read quiz history: %v", err)
+	}
+	if index != 1 {
+		t.Errorf("Expected migrated question index 1, got %d", index)
+	}
+}
+
+func TestQuizStoreRetries(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_retries.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Retries", []string{"q3", "q1"})
+	d.MaxTries = 3
+	d.RetryDecay = []float64{1, 0.5}
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	q, err := store.StartAttempt("def1", "a1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	if q.RemainingTries() != 3 {
+		t.Errorf("Expected 3 tries, got %d", q.RemainingTries())
+	}
+	q.SubmitAnswer("London")
+	q.SubmitAnswer("Paris")
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz with retries: %v", err)
+	}
+
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if resumed.GetDefinition().MaxTries != 3 || len(resumed.GetDefinition().RetryDecay) != 2 {
+		t.Errorf("Expected retry settings to round trip, got %d tries and decay %v",
+			resumed.GetDefinition().MaxTries, resumed.GetDefinition().RetryDecay)
+	}
+	history := resumed.GetQuestionHistory()
+	if len(history) != 2 || history[0].Try != 1 || history[1].Try != 2 {
+		t.Fatalf("Expected tries 1 and 2 to be stored, got %v", history)
+	}
+	if resumed.GetScore() != 1.5 {
+		t.Errorf("Expected score 1.5, got %v", resumed.GetScore())
+	}
+	if resumed.RemainingTries() != 0 {
+		t.Errorf("Expected no tries left after a correct answer, got %d", resumed.RemainingTries())
+	}
+
+	// A wrong answer on the next question leaves tries to use after resuming
+	resumed.NextQuestion()
+	resumed.SubmitAnswer("3")
+	if err := store.SaveQuiz(resumed); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+	resumed, err = store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if resumed.RemainingTries() != 2 {
+		t.Errorf("Expected 2 tries left, got %d", resumed.RemainingTries())
+	}
+}
+
+func TestQuizStoreTimeLimits(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_time_limits.db"
+	defer os.Remove(dbPath)
+
+	questions := []quiz.Questioner{
+		&quiz.MultiChoice{Id: "q1", Prompt: "What is 2+2?", Options: []string{"3", "4"}, Difficulty: 1, Answer: "4", TimeLimit: 10 * time.Second},
+		&quiz.TrueFalse{Id: "q2", Prompt: "The sky is blue", Difficulty: 2, Answer: true},
+	}
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Timed", []string{"q1", "q2"})
+	d.TimeLimit = time.Hour
+	d.LatePolicy = quiz.REJECT_LATE
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	// Start an attempt two hours ago and answer in time
+	clock := quiz.NewFakeClock(time.Now().Add(-2 * time.Hour))
+	q, err := quiz.NewAttemptWithOptions("a1", d, questions, quiz.Options{Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	clock.Advance(5 * time.Second)
+	q.SubmitAnswer("4")
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	// Answer too late in an attempt that gives late answers no credit
+	lateClock := quiz.NewFakeClock(time.Now())
+	late := quiz.NewQuizWithOptions("a2", questions, quiz.Options{Clock: lateClock})
+	lateClock.Advance(15 * time.Second)
+	late.SubmitAnswer("4")
+	if err := store.SaveQuiz(late); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	stored, err := definitions.GetDefinition("def1")
+	if err != nil {
+		t.Fatalf("Failed to get definition: %v", err)
+	}
+	if stored.LatePolicy != quiz.REJECT_LATE {
+		t.Errorf("Expected late policy REJECT_LATE, got %s", stored.LatePolicy)
+	}
+
+	resumedLate, err := store.GetQuiz("a2")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	history := resumedLate.GetQuestionHistory()
+	if len(history) != 1 || !history[0].Late || history[0].Grade.ScoreAwarded != 0 {
+		t.Errorf("Expected a late answer without credit to be stored, got %+v", history)
+	}
+	if history[0].TimeTaken != 15*time.Second {
+		t.Errorf("Expected time taken 15s, got %v", history[0].TimeTaken)
+	}
+
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if resumed.GetScore() != 1 {
+		t.Errorf("Expected score 1, got %v", resumed.GetScore())
+	}
+
+	// The stored start time puts the attempt past its deadline
+	if !resumed.CheckExpiry() {
+		t.Errorf("Expected the attempt to expire")
+	}
+	if resumed.GetStatus() != quiz.EXPIRED {
+		t.Errorf("Expected status EXPIRED, got %s", resumed.GetStatus())
+	}
+	if _, err := resumed.Submit(quiz.TextAnswer("true")); err != quiz.ErrQuizExpired {
+		t.Errorf("Expected ErrQuizExpired, got %v", err)
+	}
+}
+
+func TestQuizStoreClockAndSeed(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_clock_seed.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	clock := quiz.NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	store.SetClock(clock)
+
+	q := quiz.NewQuizWithOptions("q1", questions, quiz.Options{Clock: clock, Seed: 7})
+	if err := store.SaveQuiz(q); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	resumed, err := store.GetQuiz("q1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if resumed.GetSeed() != 7 {
+		t.Errorf("Expected seed 7, got %d", resumed.GetSeed())
+	}
+
+	// The resumed attempt reads the time from the store's clock
+	clock.Advance(3 * time.Second)
+	resumed.SubmitAnswer("4")
+	if taken := resumed.GetQuestionHistory()[0].TimeTaken; taken != 3*time.Second {
+		t.Errorf("Expected time taken 3s, got %v", taken)
+	}
+}
+
+func TestQuizStoreShuffledAttempt(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_shuffle.db"
+	defer os.Remove(dbPath)
+
+	questions := []quiz.Questioner{
+		&quiz.MultiChoice{Id: "q1", Prompt: "What is 2+2?", Options: []string{"3", "4", "5", "None of the above"}, PinnedOptions: []int{3}, Difficulty: 1, Answer: "4"},
+		&quiz.MultiResponse{Id: "q2", Prompt: "Select the even numbers", Options: []string{"1", "2", "3", "4"}, Difficulty: 2, CorrectAnswerIndices: []int{1, 3}},
+		&quiz.TrueFalse{Id: "q3", Prompt: "The sky is blue", Difficulty: 1, Answer: true},
+		&quiz.Ordering{Id: "q4", Prompt: "Order from smallest", Items: []string{"1", "2", "3"}, CorrectOrderIndices: []int{0, 1, 2}, Difficulty: 1},
+	}
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Shuffled", []string{"q1", "q2", "q3", "q4"})
+	d.ShuffleQuestions = true
+	d.ShuffleAnswers = true
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	attempt, err := store.StartAttempt("def1", "a1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if !reflect.DeepEqual(questionIDs(resumed), questionIDs(attempt)) {
+		t.Errorf("Expected question order %v, got %v", questionIDs(attempt), questionIDs(resumed))
+	}
+	for i := range attempt.GetQuestions() {
+		if !reflect.DeepEqual(resumed.GetOptionOrder(i), attempt.GetOptionOrder(i)) {
+			t.Errorf("Expected option order %v, got %v", attempt.GetOptionOrder(i), resumed.GetOptionOrder(i))
+		}
+		if !reflect.DeepEqual(resumed.GetPresentedOptions(i), attempt.GetPresentedOptions(i)) {
+			t.Errorf("Expected options %v, got %v", attempt.GetPresentedOptions(i), resumed.GetPresentedOptions(i))
+		}
+	}
+
+	// Ordering items are shuffled with the other options
+	for i, question := range attempt.GetQuestions() {
+		if question.GetID() == "q4" && resumed.GetOptionOrder(i) == nil {
+			t.Error("Expected the Ordering question to have an option order")
+		}
+	}
+
+	// Pinned options survive the question store
+	stored, err := store.questionStore.GetQuestion("q1")
+	if err != nil {
+		t.Fatalf("Failed to get question: %v", err)
+	}
+	if pinned := stored.(*quiz.MultiChoice).PinnedOptions; !reflect.DeepEqual(pinned, []int{3}) {
+		t.Errorf("Expected pinned options [3], got %v", pinned)
+	}
+}
+
+func TestQuizStoreDrawsFromPools(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_pools.db"
+	defer os.Remove(dbPath)
+
+	var questions []quiz.Questioner
+	for _, id := range []string{"n1", "n2", "n3", "n4", "g1", "g2"} {
+		questions = append(questions, &quiz.TrueFalse{Id: id, Prompt: "Statement " + id, Difficulty: 2, Answer: true})
+	}
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+	for _, q := range questions {
+		tag := "networking"
+		if q.GetID()[0] == 'g' {
+			tag = "go"
+		}
+		if err := store.questionStore.SetTags(q.GetID(), tag); err != nil {
+			t.Fatalf("Failed to set tags: %v", err)
+		}
+	}
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Pooled", nil)
+	d.Pools = []quiz.QuestionPool{
+		{Id: "net", QuestionFilter: quiz.QuestionFilter{Tags: []string{"networking"}, MinDifficulty: 2, MaxDifficulty: 3}, Draw: 2},
+		{Id: "go", QuestionFilter: quiz.QuestionFilter{Tags: []string{"go"}}, Draw: 1},
+	}
+	d.MinimizeOverlap = true
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	stored, err := definitions.GetDefinition("def1")
+	if err != nil {
+		t.Fatalf("Failed to get definition: %v", err)
+	}
+	if !reflect.DeepEqual(stored.Pools, d.Pools) || !stored.MinimizeOverlap {
+		t.Errorf("Expected pools %+v, got %+v", d.Pools, stored.Pools)
+	}
+
+	first, err := store.StartAttempt("def1", "a1", "u1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	second, err := store.StartAttempt("def1", "a2", "u1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+
+	// The second attempt draws the networking questions the first did not
+	drawn := make(map[string]bool)
+	for i, q := range append(first.GetQuestions(), second.GetQuestions()...) {
+		if q.GetID()[0] == 'n' {
+			if drawn[q.GetID()] {
+				t.Errorf("Expected no overlap in the networking pool, got %s twice", q.GetID())
+			}
+			drawn[q.GetID()] = true
+		}
+		if i >= 3 {
+			i -= 3
+		}
+		want := "net"
+		if i == 2 {
+			want = "go"
+		}
+		if q.GetID()[0] != want[0] {
+			t.Errorf("Expected question %d from pool %s, got %s", i, want, q.GetID())
+		}
+	}
+
+	resumed, err := store.GetQuiz("a2")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if !reflect.DeepEqual(questionIDs(resumed), questionIDs(second)) {
+		t.Errorf("Expected drawn questions %v, got %v", questionIDs(second), questionIDs(resumed))
+	}
+	for i := range second.GetQuestions() {
+		if resumed.GetQuestionPool(i) != second.GetQuestionPool(i) {
+			t.Errorf("Expected pool %q at %d, got %q", second.GetQuestionPool(i), i, resumed.GetQuestionPool(i))
+		}
+	}
+}
+
+func TestQuizStoreNavigation(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_navigation.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Navigable", questionIDs(quiz.NewQuiz("ids", questions)))
+	d.Navigation = quiz.FREE_NAVIGATION
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	attempt, err := store.StartAttempt("def1", "a1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	clock := quiz.NewFakeClock(attempt.GetPresentedAt())
+	attempt.SetClock(clock)
+	clock.Advance(20 * time.Second)
+	last := attempt.AmountOfQuestions() - 1
+	if err := attempt.GoTo(last); err != nil {
+		t.Fatalf("Failed to go to the last question: %v", err)
+	}
+	if err := attempt.Flag(0); err != nil {
+		t.Fatalf("Failed to flag: %v", err)
+	}
+	if err := attempt.Flag(last); err != nil {
+		t.Fatalf("Failed to flag: %v", err)
+	}
+	if err := store.SaveQuiz(attempt); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if resumed.GetDefinition().Navigation != quiz.FREE_NAVIGATION {
+		t.Errorf("Expected navigation FREE_NAVIGATION, got %s", resumed.GetDefinition().Navigation)
+	}
+	if resumed.GetCurrentIndex() != last {
+		t.Errorf("Expected current index %d, got %d", last, resumed.GetCurrentIndex())
+	}
+	if got := resumed.FlaggedQuestions(); !reflect.DeepEqual(got, []int{0, last}) {
+		t.Errorf("Expected flagged [0 %d], got %v", last, got)
+	}
+	if got := resumed.GetTimeSpent(0); got != 20*time.Second {
+		t.Errorf("Expected 20s spent on the first question, got %v", got)
+	}
+	if !resumed.PreviousQuestion() {
+		t.Errorf("Expected the resumed attempt to move back")
+	}
+}
+
+func TestQuizStoreAttemptSettings(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_attempt_settings.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Settings", questionIDs(quiz.NewQuiz("ids", questions)))
+	d.TimeLimit = 10 * time.Minute
+	d.MaxTries = 3
+	d.PassingScore = 50
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+	attempt, err := store.StartAttempt("def1", "a1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	want := attempt.GetSettings()
+
+	// Editing the definition does not change the attempt in progress
+	d.TimeLimit = time.Minute
+	d.MaxTries = 1
+	d.PassingScore = 90
+	d.Navigation = quiz.FREE_NAVIGATION
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to update definition: %v", err)
+	}
+
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if got := resumed.GetSettings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected settings %+v, got %+v", want, got)
+	}
+	if resumed.GetDefinition().MaxTries != 1 {
+		t.Errorf("Expected the definition to be updated, got %d tries", resumed.GetDefinition().MaxTries)
+	}
+}
+
+func TestQuizStorePausedAttempts(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_paused.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	clock := quiz.NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	store.SetClock(clock)
+
+	paused := quiz.NewQuizWithOptions("a1", questions, quiz.Options{Clock: clock})
+	paused.AddUser("u1")
+	clock.Advance(30 * time.Second)
+	if err := paused.Pause(); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	other := quiz.NewQuizWithOptions("a2", questions, quiz.Options{Clock: clock})
+	other.AddUser("u2")
+	if err := other.Pause(); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	running := quiz.NewQuizWithOptions("a3", questions, quiz.Options{Clock: clock})
+	running.AddUser("u1")
+	for _, q := range []*quiz.Quiz{paused, other, running} {
+		if err := store.SaveQuiz(q); err != nil {
+			t.Fatalf("Failed to save quiz: %v", err)
+		}
+	}
+
+	all, err := store.ListPausedAttempts("")
+	if err != nil {
+		t.Fatalf("Failed to list paused attempts: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 paused attempts, got %d", len(all))
+	}
+	mine, err := store.ListPausedAttempts("u1")
+	if err != nil {
+		t.Fatalf("Failed to list paused attempts: %v", err)
+	}
+	if len(mine) != 1 || mine[0].Id != "a1" {
+		t.Fatalf("Expected paused attempt a1, got %v", mine)
+	}
+	if mine[0].GetStatus() != quiz.SAVED || !mine[0].GetPausedAt().Equal(clock.Now()) {
+		t.Errorf("Expected a SAVED attempt paused at %v, got %s at %v", clock.Now(), mine[0].GetStatus(), mine[0].GetPausedAt())
+	}
+
+	// Resuming an hour later does not count the pause
+	clock.Advance(time.Hour)
+	resumed, err := store.ResumeAttempt("a1")
+	if err != nil {
+		t.Fatalf("Failed to resume attempt: %v", err)
+	}
+	if resumed.GetStatus() != quiz.STARTED {
+		t.Errorf("Expected status STARTED, got %s", resumed.GetStatus())
+	}
+
+	stored, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if stored.GetPausedTime() != time.Hour {
+		t.Errorf("Expected paused time 1h, got %v", stored.GetPausedTime())
+	}
+	if stored.GetTimeTaken() != 30*time.Second {
+		t.Errorf("Expected time taken 30s, got %v", stored.GetTimeTaken())
+	}
+	if _, err := store.ResumeAttempt("a3"); !errors.Is(err, quiz.ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestQuizStoreHints(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_hints.db"
+	defer os.Remove(dbPath)
+
+	questions := []quiz.Questioner{
+		&quiz.MultiChoice{
+			Id:         "q1",
+			Prompt:     "What is 2+2?",
+			Options:    []string{"3", "4"},
+			Difficulty: 4,
+			Answer:     "4",
+			Hint:       "It is even",
+			Hints:      []string{"It is less than 5", "It is 2 squared"},
+		},
+		&quiz.Numeric{Id: "q2", Prompt: "How many legs does a spider have?", Difficulty: 2, CorrectAnswer: 8, Hints: []string{"More than 6"}},
+	}
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	// Progressive hints round-trip through the question store
+	loaded, err := store.questionStore.GetQuestion("q1")
+	if err != nil {
+		t.Fatalf("Failed to get question: %v", err)
+	}
+	if got := loaded.GetHints(); !reflect.DeepEqual(got, questions[0].GetHints()) {
+		t.Errorf("Expected hints %v, got %v", questions[0].GetHints(), got)
+	}
+	loaded, err = store.questionStore.GetQuestion("q2")
+	if err != nil {
+		t.Fatalf("Failed to get question: %v", err)
+	}
+	if got := loaded.GetHints(); !reflect.DeepEqual(got, []string{"More than 6"}) {
+		t.Errorf("Expected hints [More than 6], got %v", got)
+	}
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Hinted", []string{"q1", "q2"})
+	d.HintPenalty = quiz.HintPenalty{Kind: quiz.FIXED_PENALTY, Amount: 1}
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+	saved, err := definitions.GetDefinition("def1")
+	if err != nil {
+		t.Fatalf("Failed to get definition: %v", err)
+	}
+	if saved.HintPenalty != d.HintPenalty {
+		t.Errorf("Expected hint penalty %+v, got %+v", d.HintPenalty, saved.HintPenalty)
+	}
+
+	attempt, err := store.StartAttempt("def1", "a1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	attempt.RevealHint()
+	attempt.RevealHint()
+	attempt.SubmitAnswer("4")
+	attempt.NextQuestion()
+	attempt.RevealHint()
+	if err := store.SaveQuiz(attempt); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if resumed.GetHintsUsed(0) != 2 || resumed.GetHintsUsed(1) != 1 {
+		t.Errorf("Expected hints used 2 and 1, got %d and %d", resumed.GetHintsUsed(0), resumed.GetHintsUsed(1))
+	}
+	history := resumed.GetQuestionHistory()
+	if len(history) != 1 || history[0].HintsUsed != 2 {
+		t.Errorf("Expected one result with 2 hints used, got %+v", history)
+	}
+	if resumed.GetScore() != 2 {
+		t.Errorf("Expected score 2, got %v", resumed.GetScore())
+	}
+	if _, err := resumed.RevealHint(); err != quiz.ErrNoHintsLeft {
+		t.Errorf("Expected ErrNoHintsLeft, got %v", err)
+	}
+}
+
+func TestQuizStoreScoring(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_scoring.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	scoring := &quiz.NegativeMarking{Points: 1, Penalty: 0.5, FloorAtZero: true}
+	d := quiz.NewQuizDefinition("def1", "Negative", questionIDs(quiz.NewQuiz("ids", questions)))
+	d.SetScoring(scoring)
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	saved, err := definitions.GetDefinition("def1")
+	if err != nil {
+		t.Fatalf("Failed to get definition: %v", err)
+	}
+	if saved.ScoringMethod != quiz.NEGATIVE_MARKING {
+		t.Errorf("Expected scoring method NEGATIVE_MARKING, got %s", saved.ScoringMethod)
+	}
+	if !reflect.DeepEqual(saved.Scoring, scoring) {
+		t.Errorf("Expected scoring %+v, got %+v", scoring, saved.Scoring)
+	}
+
+	attempt, err := store.StartAttempt("def1", "a1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	attempt.SubmitAnswer("Rome")
+	if err := store.SaveQuiz(attempt); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	// Rescoring the definition leaves the stored attempt as it was scored
+	saved.SetScoring(&quiz.CustomPoints{Points: map[string]float64{"q3": 5}})
+	if err := definitions.SaveDefinition(saved); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if !reflect.DeepEqual(resumed.GetScoring(), scoring) {
+		t.Errorf("Expected scoring %+v, got %+v", scoring, resumed.GetScoring())
+	}
+	if resumed.TotalPoints() != -0.5 || resumed.GetScore() != 0 {
+		t.Errorf("Expected total points -0.5 and score 0, got %v and %v", resumed.TotalPoints(), resumed.GetScore())
+	}
+	if resumed.GetDefinition().ScoringMethod != quiz.CUSTOM_POINTS {
+		t.Errorf("Expected the definition to use CUSTOM_POINTS, got %s", resumed.GetDefinition().ScoringMethod)
+	}
+}
+
+func TestQuizStoreAdaptive(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_adaptive.db"
+	defer os.Remove(dbPath)
+
+	var questions []quiz.Questioner
+	for difficulty := 1; difficulty <= 4; difficulty++ {
+		questions = append(questions, &quiz.TrueFalse{
+			Id:         "level" + strconv.Itoa(difficulty),
+			Prompt:     "Statement " + strconv.Itoa(difficulty),
+			Difficulty: difficulty,
+			Answer:     true,
+		})
+	}
+	store := openResumeStore(t, dbPath, append(questions, &quiz.TrueFalse{Id: "other", Prompt: "Untagged", Difficulty: 2}))
+	defer store.Close()
+	for _, question := range questions {
+		if err := store.questionStore.SetTags(question.GetID(), "adaptive"); err != nil {
+			t.Fatalf("Failed to tag question: %v", err)
+		}
+	}
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Adaptive", nil)
+	d.Adaptive = &quiz.AdaptivePolicy{
+		QuestionFilter:  quiz.QuestionFilter{Tags: []string{"adaptive"}},
+		StartDifficulty: 2,
+		Stop:            quiz.StopRule{MaxQuestions: 3},
+	}
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+	saved, err := definitions.GetDefinition("def1")
+	if err != nil {
+		t.Fatalf("Failed to get definition: %v", err)
+	}
+	if !reflect.DeepEqual(saved.Adaptive, d.Adaptive) {
+		t.Errorf("Expected adaptive policy %+v, got %+v", d.Adaptive, saved.Adaptive)
+	}
+
+	attempt, err := store.StartAttempt("def1", "a1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	if attempt.GetQuestions()[0].GetID() != "level2" {
+		t.Errorf("Expected the attempt to start at level2, got %s", attempt.GetQuestions()[0].GetID())
+	}
+	attempt.SubmitAnswer("true")
+	attempt.NextQuestion()
+	if err := store.SaveQuiz(attempt); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if !reflect.DeepEqual(resumed.GetAdaptiveTrace(), attempt.GetAdaptiveTrace()) {
+		t.Errorf("Expected trace %+v, got %+v", attempt.GetAdaptiveTrace(), resumed.GetAdaptiveTrace())
+	}
+	if got := questionIDs(resumed); !reflect.DeepEqual(got, []string{"level2", "level3"}) {
+		t.Errorf("Expected questions [level2 level3], got %v", got)
+	}
+
+	if got := len(resumed.GetAdaptiveCandidates()); got != 4 {
+		t.Errorf("Expected 4 candidates, got %d", got)
+	}
+
+	// Retagging a question after the attempt started does not change the
+	// questions it picks from
+	if err := store.questionStore.SetTags("level4"); err != nil {
+		t.Fatalf("Failed to retag question: %v", err)
+	}
+	if err := store.questionStore.SetTags("other", "adaptive"); err != nil {
+		t.Fatalf("Failed to retag question: %v", err)
+	}
+
+	// Deleting a candidate that was not picked leaves it out
+	if err := store.questionStore.DeleteQuestion("level1"); err != nil {
+		t.Fatalf("Failed to delete question: %v", err)
+	}
+	resumed, err = store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if got := len(resumed.GetAdaptiveCandidates()); got != 3 {
+		t.Errorf("Expected 3 candidates, got %d", got)
+	}
+
+	// The resumed attempt keeps picking from the questions tagged when it
+	// started
+	resumed.SubmitAnswer("true")
+	if !resumed.NextQuestion() {
+		t.Fatalf("Expected the resumed attempt to pick another question")
+	}
+	if got := resumed.CurrentQuestion().GetID(); got != "level4" {
+		t.Errorf("Expected level4, got %s", got)
+	}
+	resumed.SubmitAnswer("true")
+	resumed.NextQuestion()
+	if !resumed.IsCompleted() {
+		t.Errorf("Expected the attempt to stop after 3 questions")
+	}
+	if err := store.SaveQuiz(resumed); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	finished, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if len(finished.GetAdaptiveTrace()) != 3 || finished.GetScore() != 9 {
+		t.Errorf("Expected 3 steps and score 9, got %d and %v", len(finished.GetAdaptiveTrace()), finished.GetScore())
+	}
+}
@@ -0,0 +1,321 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/BurningIceCube/quizine/pkg/quiz"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ResultStore keeps the results of finished attempts. Results copy the
+// scores and prompts they were computed from, so they stay as they were
+// when questions are edited later.
+type ResultStore struct {
+	db *sql.DB
+}
+
+func NewResultStore(dbPath string) (*ResultStore, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	// Create tables if they don't exist
+	if err := createResultTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create tables: %v", err)
+	}
+
+	return &ResultStore{db: db}, nil
+}
+
+func createResultTables(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS quiz_results (
+		attempt_id TEXT PRIMARY KEY,
+		definition_id TEXT NOT NULL,
+		status TEXT NOT NULL,
+		scoring_method TEXT NOT NULL,
+		score REAL NOT NULL,
+		max_score REAL NOT NULL,
+		percentage REAL NOT NULL,
+		passing_score REAL NOT NULL,
+		passed BOOLEAN NOT NULL,
+		completion_time INTEGER NOT NULL,
+		completed_at TIMESTAMP NOT NULL,
+		FOREIGN KEY (attempt_id) REFERENCES quizzes(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS quiz_result_users (
+		attempt_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		user_id TEXT NOT NULL,
+		FOREIGN KEY (attempt_id) REFERENCES quiz_results(attempt_id),
+		PRIMARY KEY (attempt_id, position)
+	);
+
+	CREATE TABLE IF NOT EXISTS quiz_result_questions (
+		attempt_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		question_id TEXT NOT NULL,
+		prompt TEXT NOT NULL,
+		answered BOOLEAN NOT NULL,
+		answer TEXT NOT NULL,
+		correct BOOLEAN NOT NULL,
+		pending BOOLEAN NOT NULL,
+		late BOOLEAN NOT NULL,
+		tries INTEGER NOT NULL,
+		hints_used INTEGER NOT NULL,
+		score REAL NOT NULL,
+		max_score REAL NOT NULL,
+		feedback TEXT NOT NULL,
+		time_taken INTEGER NOT NULL,
+		FOREIGN KEY (attempt_id) REFERENCES quiz_results(attempt_id),
+		PRIMARY KEY (attempt_id, position)
+	);`
+
+	_, err := db.Exec(createTableSQL)
+	return err
+}
+
+func (rs *ResultStore) Close() error {
+	return rs.db.Close()
+}
+
+// SaveResult creates or replaces the result of an attempt.
+func (rs *ResultStore) SaveResult(r *quiz.QuizResult) error {
+	tx, err := rs.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := saveResult(tx, r); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// saveResult writes r and its breakdown within tx.
+func saveResult(tx *sql.Tx, r *quiz.QuizResult) error {
+	if err := deleteResult(tx, r.AttemptID); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`
+	INSERT INTO quiz_results (attempt_id, definition_id, status, scoring_method, score, max_score, percentage, passing_score, passed, completion_time, completed_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.AttemptID,
+		r.DefinitionID,
+		string(r.Status),
+		string(r.ScoringMethod),
+		r.Score,
+		r.MaxScore,
+		r.Percentage,
+		r.PassingScore,
+		r.Passed,
+		r.CompletionTime.Milliseconds(),
+		r.CompletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save quiz result: %v", err)
+	}
+
+	for i, userID := range r.UserIDs {
+		_, err = tx.Exec("INSERT INTO quiz_result_users (attempt_id, position, user_id) VALUES (?, ?, ?)", r.AttemptID, i, userID)
+		if err != nil {
+			return fmt.Errorf("failed to save result user: %v", err)
+		}
+	}
+
+	for _, o := range r.Breakdown {
+		_, err = tx.Exec("INSERT INTO quiz_result_questions (attempt_id, position, question_id, prompt, answered, answer, correct, pending, late, tries, hints_used, score, max_score, feedback, time_taken) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			r.AttemptID, o.QuestionIndex, o.QuestionID, o.Prompt, o.Answered, o.Answer, o.Correct, o.Pending, o.Late, o.Tries, o.HintsUsed,
+			o.ScoreAwarded, o.MaxScore, o.Feedback, o.TimeTaken.Milliseconds())
+		if err != nil {
+			return fmt.Errorf("failed to save result question: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// resultExists reports whether a result is stored for the attempt.
+func resultExists(db sqlRunner, attemptID string) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM quiz_results WHERE attempt_id = ?", attemptID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check quiz result: %v", err)
+	}
+	return count > 0, nil
+}
+
+func (rs *ResultStore) GetResult(attemptID string) (*quiz.QuizResult, error) {
+	return getResult(rs.db, attemptID)
+}
+
+// getResult reads the result of an attempt and its breakdown.
+func getResult(db sqlRunner, attemptID string) (*quiz.QuizResult, error) {
+	query := `
+	SELECT definition_id, status, scoring_method, score, max_score, percentage, passing_score, passed, completion_time, completed_at
+	FROM quiz_results
+	WHERE attempt_id = ?`
+
+	var (
+		r              = &quiz.QuizResult{AttemptID: attemptID}
+		status         string
+		scoringMethod  string
+		completionTime int64
+	)
+
+	err := db.QueryRow(query, attemptID).Scan(
+		&r.DefinitionID,
+		&status,
+		&scoringMethod,
+		&r.Score,
+		&r.MaxScore,
+		&r.Percentage,
+		&r.PassingScore,
+		&r.Passed,
+		&completionTime,
+		&r.CompletedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz result: %v", err)
+	}
+	r.Status = quiz.QuizStatus(status)
+	r.ScoringMethod = quiz.ScoringMethod(scoringMethod)
+	r.CompletionTime = time.Duration(completionTime) * time.Millisecond
+
+	userRows, err := db.Query("SELECT user_id FROM quiz_result_users WHERE attempt_id = ? ORDER BY position", attemptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get result users: %v", err)
+	}
+	defer userRows.Close()
+
+	for userRows.Next() {
+		var userID string
+		if err := userRows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %v", err)
+		}
+		r.UserIDs = append(r.UserIDs, userID)
+	}
+	if err := userRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get result users: %v", err)
+	}
+
+	rows, err := db.Query("SELECT position, question_id, prompt, answered, answer, correct, pending, late, tries, hints_used, score, max_score, feedback, time_taken FROM quiz_result_questions WHERE attempt_id = ? ORDER BY position", attemptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get result questions: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			o         quiz.QuestionOutcome
+			timeTaken int64
+		)
+		err := rows.Scan(&o.QuestionIndex, &o.QuestionID, &o.Prompt, &o.Answered, &o.Answer, &o.Correct, &o.Pending, &o.Late, &o.Tries, &o.HintsUsed,
+			&o.ScoreAwarded, &o.MaxScore, &o.Feedback, &timeTaken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan result question: %v", err)
+		}
+		o.TimeTaken = time.Duration(timeTaken) * time.Millisecond
+		r.Breakdown = append(r.Breakdown, o)
+	}
+
+	return r, rows.Err()
+}
+
+// DeleteResult removes the result of an attempt.
+func (rs *ResultStore) DeleteResult(attemptID string) error {
+	tx, err := rs.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteResult(tx, attemptID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteResult removes the result of an attempt within tx.
+func deleteResult(tx *sql.Tx, attemptID string) error {
+	_, err := tx.Exec("DELETE FROM quiz_result_questions WHERE attempt_id = ?", attemptID)
+	if err != nil {
+		return fmt.Errorf("failed to delete result questions: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM quiz_result_users WHERE attempt_id = ?", attemptID)
+	if err != nil {
+		return fmt.Errorf("failed to delete result users: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM quiz_results WHERE attempt_id = ?", attemptID)
+	if err != nil {
+		return fmt.Errorf("failed to delete quiz result: %v", err)
+	}
+
+	return nil
+}
+
+// ListResults returns the results of a definition's attempts, or of every
+// attempt if definitionID is empty, in the order they were completed.
+func (rs *ResultStore) ListResults(definitionID string) ([]*quiz.QuizResult, error) {
+	return rs.listResults("SELECT attempt_id FROM quiz_results WHERE ? = '' OR definition_id = ? ORDER BY completed_at, attempt_id", definitionID, definitionID)
+}
+
+// ListUserResults returns the results of the attempts a user took part in,
+// in the order they were completed.
+func (rs *ResultStore) ListUserResults(userID string) ([]*quiz.QuizResult, error) {
+	query := `
+	SELECT r.attempt_id FROM quiz_results r
+	JOIN quiz_result_users u ON u.attempt_id = r.attempt_id
+	WHERE u.user_id = ?
+	ORDER BY r.completed_at, r.attempt_id`
+
+	return rs.listResults(query, userID)
+}
+
+// listResults reads the results whose attempt IDs are selected by query.
+func (rs *ResultStore) listResults(query string, args ...any) ([]*quiz.QuizResult, error) {
+	rows, err := rs.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quiz results: %v", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan attempt ID: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list quiz results: %v", err)
+	}
+
+	var results []*quiz.QuizResult
+	for _, id := range ids {
+		r, err := rs.GetResult(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get quiz result %s: %v", id, err)
+		}
+		results = append(results, r)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/BurningIceCube/quizine/pkg/quiz"
+)
+
+func TestResultStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_results.db"
+	defer os.Remove(dbPath)
+
+	questions := resumeTestQuestions()
+	store := openResumeStore(t, dbPath, questions)
+	defer store.Close()
+
+	results, err := NewResultStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create result store: %v", err)
+	}
+	defer results.Close()
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Results", questionIDs(quiz.NewQuiz("ids", questions)))
+	d.PassingScore = 50
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	clock := quiz.NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	store.SetClock(clock)
+	attempt, err := store.StartAttempt("def1", "a1", "u1")
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	if err := store.SaveQuiz(attempt); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+	if _, err := results.GetResult("a1"); err == nil {
+		t.Errorf("Expected no result for an unfinished attempt")
+	}
+
+	// Answer the fill-in wrongly and the rest correctly
+	for _, answer := range []string{"Lyon", "4", "false", "8"} {
+		clock.Advance(time.Second)
+		attempt.SubmitAnswer(answer)
+		attempt.NextQuestion()
+	}
+	if err := store.SaveQuiz(attempt); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+
+	want, err := attempt.Result()
+	if err != nil {
+		t.Fatalf("Failed to get result: %v", err)
+	}
+	got, err := results.GetResult("a1")
+	if err != nil {
+		t.Fatalf("Failed to get stored result: %v", err)
+	}
+	if !got.CompletedAt.Equal(want.CompletedAt) {
+		t.Errorf("Expected completed at %v, got %v", want.CompletedAt, got.CompletedAt)
+	}
+	got.CompletedAt = want.CompletedAt
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected result %+v, got %+v", want, got)
+	}
+	if got.Score != 5 || got.MaxScore != 8 || !got.Passed {
+		t.Errorf("Expected a passing score of 5 of 8, got %v of %v", got.Score, got.MaxScore)
+	}
+
+	// Editing a question and saving the attempt again keeps the result
+	fillIn := questions[0].(*quiz.FillIn)
+	edited := *fillIn
+	edited.Prompt = "The largest city of France is ___"
+	edited.Answer = "Lyon"
+	if err := store.questionStore.SaveQuestion(&edited); err != nil {
+		t.Fatalf("Failed to save question: %v", err)
+	}
+	resumed, err := store.GetQuiz("a1")
+	if err != nil {
+		t.Fatalf("Failed to get quiz: %v", err)
+	}
+	if err := store.SaveQuiz(resumed); err != nil {
+		t.Fatalf("Failed to save quiz: %v", err)
+	}
+	got, err = results.GetResult("a1")
+	if err != nil {
+		t.Fatalf("Failed to get stored result: %v", err)
+	}
+	if got.Breakdown[0].Prompt != fillIn.Prompt || got.Score != 5 {
+		t.Errorf("Expected the stored result to keep prompt %q and score 5, got %q and %v", fillIn.Prompt, got.Breakdown[0].Prompt, got.Score)
+	}
+
+	// Regrading replaces it
+	if _, err := store.RegradeAttempt("a1"); err != nil {
+		t.Fatalf("Failed to regrade: %v", err)
+	}
+	got, err = results.GetResult("a1")
+	if err != nil {
+		t.Fatalf("Failed to get stored result: %v", err)
+	}
+	if got.Score != 8 || got.Breakdown[0].Prompt != edited.Prompt {
+		t.Errorf("Expected the regraded result to score 8 with the edited prompt, got %v and %q", got.Score, got.Breakdown[0].Prompt)
+	}
+
+	listed, err := results.ListResults("def1")
+	if err != nil {
+		t.Fatalf("Failed to list results: %v", err)
+	}
+	if len(listed) != 1 || listed[0].AttemptID != "a1" {
+		t.Errorf("Expected the result of a1, got %v", listed)
+	}
+	listed, err = results.ListUserResults("u1")
+	if err != nil {
+		t.Fatalf("Failed to list user results: %v", err)
+	}
+	if len(listed) != 1 {
+		t.Errorf("Expected 1 result for u1, got %d", len(listed))
+	}
+	listed, err = results.ListUserResults("u2")
+	if err != nil {
+		t.Fatalf("Failed to list user results: %v", err)
+	}
+	if len(listed) != 0 {
+		t.Errorf("Expected no results for u2, got %d", len(listed))
+	}
+
+	if err := results.DeleteResult("a1"); err != nil {
+		t.Fatalf("Failed to delete result: %v", err)
+	}
+	if _, err := results.GetResult("a1"); err == nil {
+		t.Errorf("Expected an error for a deleted result")
+	}
+}
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// columnExists reports whether table already has the named column.
+func columnExists(db *sql.DB, table, column string) (bool, error) {
+	_, exists, err := columnType(db, table, column)
+	return exists, err
+}
+
+// columnType returns the declared type of the named column and whether
+// table has it.
+func columnType(db *sql.DB, table, column string) (string, bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return "", false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    bool
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return "", false, err
+		}
+		if name == column {
+			return colType, true, nil
+		}
+	}
+	return "", false, rows.Err()
+}
+
+// addColumn adds a column to a table created by an older version of the
+// schema. It does nothing if the column already exists.
+func addColumn(db *sql.DB, table, column, definition string) error {
+	exists, err := columnExists(db, table, column)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %v", table, err)
+	}
+	if exists {
+		return nil
+	}
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// rebuildTable recreates a table created by an older version of the schema
+// whose keys have changed, which SQLite cannot alter in place. It does nothing
+// if the table already has the marker column. Otherwise the old table is
+// renamed to <table>_old, createSQL creates the new table and copySQL fills
+// it from <table>_old, which is then dropped.
+func rebuildTable(db *sql.DB, table, marker, createSQL, copySQL string) error {
+	exists, err := columnExists(db, table, marker)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %v", table, err)
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s_old", table, table),
+		createSQL,
+		copySQL,
+		fmt.Sprintf("DROP TABLE %s_old", table),
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to rebuild table %s: %v", table, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// retypeColumn changes the type of a column created by an older version of
+// the schema. It does nothing if the column already has the type given at
+// the start of definition. SQLite cannot change a column's type in place, so
+// the values are copied into a new column that then takes the old one's
+// name. Unlike rebuildTable this keeps the references other tables hold to
+// the table.
+func retypeColumn(db *sql.DB, table, column, definition string) error {
+	current, exists, err := columnType(db, table, column)
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %v", table, err)
+	}
+	if !exists || strings.HasPrefix(definition, current+" ") {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s_new %s", table, column, definition),
+		fmt.Sprintf("UPDATE %s SET %s_new = %s", table, column, column),
+		fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, column),
+		fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s_new TO %s", table, column, column),
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to change column %s.%s: %v", table, column, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/BurningIceCube/quizine/pkg/quiz"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type UserStore struct {
+	db *sql.DB
+}
+
+func NewUserStore(dbPath string) (*UserStore, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %v", err)
+	}
+
+	// Create tables if they don't exist
+	if err := createUserTables(db); err != nil {
+		return nil, fmt.Errorf("failed to create tables: %v", err)
+	}
+
+	return &UserStore{db: db}, nil
+}
+
+func createUserTables(db *sql.DB) error {
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS users (
+		id TEXT PRIMARY KEY,
+		email TEXT NOT NULL UNIQUE,
+		display_name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS user_roles (
+		user_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		PRIMARY KEY (user_id, role)
+	);
+
+	CREATE TABLE IF NOT EXISTS attempt_users (
+		quiz_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		FOREIGN KEY (quiz_id) REFERENCES quizzes(id),
+		FOREIGN KEY (user_id) REFERENCES users(id),
+		PRIMARY KEY (quiz_id, user_id)
+	);`
+
+	_, err := db.Exec(createTableSQL)
+	return err
+}
+
+func (us *UserStore) Close() error {
+	return us.db.Close()
+}
+
+// SaveUser creates or updates a user and their roles.
+func (us *UserStore) SaveUser(u *quiz.User) error {
+	tx, err := us.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO users (id, email, display_name, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT(id) DO UPDATE SET
+		email = excluded.email,
+		display_name = excluded.display_name,
+		updated_at = excluded.updated_at`
+
+	_, err = tx.Exec(query, u.Id, u.Email, u.DisplayName, u.CreatedAt, u.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save user: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM user_roles WHERE user_id = ?", u.Id)
+	if err != nil {
+		return fmt.Errorf("failed to clear user roles: %v", err)
+	}
+
+	for _, role := range u.Roles {
+		_, err = tx.Exec("INSERT OR IGNORE INTO user_roles (user_id, role) VALUES (?, ?)", u.Id, string(role))
+		if err != nil {
+			return fmt.Errorf("failed to save user role: %v", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (us *UserStore) GetUser(id string) (*quiz.User, error) {
+	return getUser(us.db, "id", id)
+}
+
+// GetUserByEmail returns the user with the given email address.
+func (us *UserStore) GetUserByEmail(email string) (*quiz.User, error) {
+	return getUser(us.db, "email", email)
+}
+
+// getUser reads the user whose column matches value, with their roles.
+func getUser(db sqlRunner, column, value string) (*quiz.User, error) {
+	query := fmt.Sprintf("SELECT id, email, display_name, created_at, updated_at FROM users WHERE %s = ?", column)
+
+	u := &quiz.User{}
+	err := db.QueryRow(query, value).Scan(&u.Id, &u.Email, &u.DisplayName, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %v", err)
+	}
+
+	rows, err := db.Query("SELECT role FROM user_roles WHERE user_id = ? ORDER BY role", u.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to scan user role: %v", err)
+		}
+		u.Roles = append(u.Roles, quiz.Role(role))
+	}
+
+	return u, rows.Err()
+}
+
+// DeleteUser removes a user and their roles. Their attempts are kept but are
+// no longer linked to them.
+func (us *UserStore) DeleteUser(id string) error {
+	tx, err := us.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM attempt_users WHERE user_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attempt users: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM user_roles WHERE user_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user roles: %v", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM users WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %v", err)
+	}
+
+	return tx.Commit()
+}
+
+// ListUsers returns every user, or only those with role if it is not empty,
+// ordered by display name.
+func (us *UserStore) ListUsers(role quiz.Role) ([]*quiz.User, error) {
+	query := `
+	SELECT id FROM users u
+	WHERE ? = '' OR EXISTS (SELECT 1 FROM user_roles r WHERE r.user_id = u.id AND r.role = ?)
+	ORDER BY display_name, id`
+
+	return listUsers(us.db, query, string(role), string(role))
+}
+
+// listUsers reads the users whose IDs are selected by query.
+func listUsers(db *sql.DB, query string, args ...any) ([]*quiz.User, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %v", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan user ID: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list users: %v", err)
+	}
+
+	var users []*quiz.User
+	for _, id := range ids {
+		u, err := getUser(db, "id", id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user %s: %v", id, err)
+		}
+		users = append(users, u)
+	}
+
+	return users, nil
+}
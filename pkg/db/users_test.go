@@ -0,0 +1,178 @@
+package db
+
+import (
+	"os"
+	"testing"
+
+	"github.com/BurningIceCube/quizine/pkg/quiz"
+)
+
+func TestUserStore(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_users.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewUserStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create user store: %v", err)
+	}
+	defer store.Close()
+
+	ada := quiz.NewUser("u1", "ada@example.com", "Ada", quiz.AUTHOR, quiz.GRADER)
+	bob := quiz.NewUser("u2", "bob@example.com", "Bob", quiz.TAKER)
+	for _, u := range []*quiz.User{ada, bob} {
+		if err := store.SaveUser(u); err != nil {
+			t.Fatalf("Failed to save user: %v", err)
+		}
+	}
+
+	retrieved, err := store.GetUser("u1")
+	if err != nil {
+		t.Fatalf("Failed to get user: %v", err)
+	}
+	if retrieved.Email != "ada@example.com" || retrieved.DisplayName != "Ada" {
+		t.Errorf("Expected Ada <ada@example.com>, got %s <%s>", retrieved.DisplayName, retrieved.Email)
+	}
+	if !retrieved.HasRole(quiz.GRADER) || retrieved.HasRole(quiz.TAKER) {
+		t.Errorf("Expected roles [AUTHOR GRADER], got %v", retrieved.Roles)
+	}
+
+	byEmail, err := store.GetUserByEmail("bob@example.com")
+	if err != nil {
+		t.Fatalf("Failed to get user by email: %v", err)
+	}
+	if byEmail.Id != "u2" {
+		t.Errorf("Expected user u2, got %s", byEmail.Id)
+	}
+
+	duplicate := quiz.NewUser("u3", "bob@example.com", "Other Bob")
+	if err := store.SaveUser(duplicate); err == nil {
+		t.Error("Expected a duplicate email to be rejected")
+	}
+
+	graders, err := store.ListUsers(quiz.GRADER)
+	if err != nil {
+		t.Fatalf("Failed to list users: %v", err)
+	}
+	if len(graders) != 1 || graders[0].Id != "u1" {
+		t.Errorf("Expected only u1 to be a grader, got %v", graders)
+	}
+	all, err := store.ListUsers("")
+	if err != nil {
+		t.Fatalf("Failed to list users: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 users, got %d", len(all))
+	}
+
+	if err := store.DeleteUser("u2"); err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+	if _, err := store.GetUser("u2"); err == nil {
+		t.Error("Expected error when getting deleted user")
+	}
+}
+
+func TestQuizStoreAttemptUsers(t *testing.T) {
+	// Skip test if CGO is disabled
+	if os.Getenv("CGO_ENABLED") == "0" {
+		t.Skip("Skipping test because CGO is disabled")
+	}
+
+	dbPath := "test_attempt_users.db"
+	defer os.Remove(dbPath)
+
+	store, err := NewQuizStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create quiz store: %v", err)
+	}
+	defer store.Close()
+
+	users, err := NewUserStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create user store: %v", err)
+	}
+	defer users.Close()
+
+	for _, u := range []*quiz.User{
+		quiz.NewUser("u1", "ada@example.com", "Ada", quiz.TAKER),
+		quiz.NewUser("u2", "bob@example.com", "Bob", quiz.TAKER),
+	} {
+		if err := users.SaveUser(u); err != nil {
+			t.Fatalf("Failed to save user: %v", err)
+		}
+	}
+
+	question := &quiz.TrueFalse{Id: "q1", Prompt: "The sky is blue", Difficulty: 1, Answer: true}
+	if err := store.questionStore.SaveQuestion(question); err != nil {
+		t.Fatalf("Failed to save question: %v", err)
+	}
+
+	definitions, err := NewDefinitionStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create definition store: %v", err)
+	}
+	defer definitions.Close()
+
+	d := quiz.NewQuizDefinition("def1", "Sky", []string{"q1"})
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if err := definitions.SaveDefinition(d); err != nil {
+		t.Fatalf("Failed to save definition: %v", err)
+	}
+
+	// A solo attempt and a group attempt
+	if _, err := store.StartAttempt("def1", "a1", "u1"); err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	if _, err := store.StartAttempt("def1", "a2", "u1", "u2"); err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+
+	group, err := store.GetQuiz("a2")
+	if err != nil {
+		t.Fatalf("Failed to get attempt: %v", err)
+	}
+	if ids := group.GetUserIDs(); len(ids) != 2 || ids[0] != "u1" || ids[1] != "u2" {
+		t.Errorf("Expected users [u1 u2], got %v", ids)
+	}
+
+	adaAttempts, err := store.ListUserAttempts("u1")
+	if err != nil {
+		t.Fatalf("Failed to list user attempts: %v", err)
+	}
+	if len(adaAttempts) != 2 {
+		t.Errorf("Expected 2 attempts for u1, got %d", len(adaAttempts))
+	}
+	bobAttempts, err := store.ListUserAttempts("u2")
+	if err != nil {
+		t.Fatalf("Failed to list user attempts: %v", err)
+	}
+	if len(bobAttempts) != 1 || bobAttempts[0].Id != "a2" {
+		t.Errorf("Expected only a2 for u2, got %v", bobAttempts)
+	}
+
+	takers, err := store.ListDefinitionUsers("def1")
+	if err != nil {
+		t.Fatalf("Failed to list definition users: %v", err)
+	}
+	if len(takers) != 2 || takers[0].DisplayName != "Ada" || takers[1].DisplayName != "Bob" {
+		t.Errorf("Expected Ada and Bob to have taken def1, got %v", takers)
+	}
+
+	if err := store.DeleteQuiz("a2"); err != nil {
+		t.Fatalf("Failed to delete attempt: %v", err)
+	}
+	bobAttempts, err = store.ListUserAttempts("u2")
+	if err != nil {
+		t.Fatalf("Failed to list user attempts: %v", err)
+	}
+	if len(bobAttempts) != 0 {
+		t.Errorf("Expected no attempts for u2, got %d", len(bobAttempts))
+	}
+}
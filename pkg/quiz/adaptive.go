@@ -0,0 +1,299 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// AdaptivePolicy makes an attempt pick its questions one at a time from the
+// questions matching its filter, following the taker's performance. The
+// difficulty steps up by Step levels after StepUpAfter correct answers in a
+// row and down after StepDownAfter wrong or skipped ones; zero values count
+// as 1. The first question is picked at StartDifficulty, or in the middle of
+// the candidates' difficulties if it is zero. Responses waiting for manual
+// grading leave the difficulty as it is.
+type AdaptivePolicy struct {
+	QuestionFilter
+	StartDifficulty int      `json:"startDifficulty"`
+	StepUpAfter     int      `json:"stepUpAfter"`
+	StepDownAfter   int      `json:"stepDownAfter"`
+	Step            int      `json:"step"`
+	Stop            StopRule `json:"stop"`
+}
+
+// StopRule decides when an adaptive attempt has asked enough questions. It
+// always stops after MaxQuestions questions or when no candidates are left.
+// With Precision set it stops earlier, once at least MinQuestions were asked
+// and the standard error of the ability estimate is at most Precision
+// difficulty levels.
+type StopRule struct {
+	MaxQuestions int     `json:"maxQuestions"`
+	MinQuestions int     `json:"minQuestions"`
+	Precision    float64 `json:"precision"`
+}
+
+// AdaptiveStep records how a question of an adaptive attempt was chosen.
+// Target is the difficulty the policy asked for and Difficulty that of the
+// closest question available. Streak counts the correct (positive) or wrong
+// (negative) answers in a row before the question was chosen, and Estimate
+// is the ability estimate at that point.
+type AdaptiveStep struct {
+	QuestionID string
+	Target     int
+	Difficulty int
+	Streak     int
+	Estimate   float64
+}
+
+// Validate checks that the policy can run an attempt.
+func (p *AdaptivePolicy) Validate() error {
+	if p.Stop.MaxQuestions < 1 {
+		return fmt.Errorf("adaptive policy must stop after at least one question")
+	}
+	if p.Stop.MinQuestions < 0 || p.Stop.MinQuestions > p.Stop.MaxQuestions {
+		return fmt.Errorf("adaptive policy minimum questions must be between 0 and %d, got %d", p.Stop.MaxQuestions, p.Stop.MinQuestions)
+	}
+	if p.Stop.Precision < 0 {
+		return fmt.Errorf("adaptive policy precision must not be negative, got %v", p.Stop.Precision)
+	}
+	if p.StartDifficulty < 0 || p.StepUpAfter < 0 || p.StepDownAfter < 0 || p.Step < 0 {
+		return fmt.Errorf("adaptive policy settings must not be negative")
+	}
+	if p.MaxDifficulty > 0 && p.MinDifficulty > p.MaxDifficulty {
+		return fmt.Errorf("adaptive policy has a minimum difficulty above its maximum")
+	}
+	return nil
+}
+
+// atLeastOne returns n, or 1 if n is zero.
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// NewAdaptiveAttempt starts an attempt of a published adaptive definition.
+// Candidates are the questions matching the definition's adaptive policy;
+// the attempt picks its first question from them now and the following
+// ones as it is answered, choosing between questions of the same difficulty
+// at random as configured by opts.
+func NewAdaptiveAttempt(id string, definition *QuizDefinition, candidates []Questioner, opts Options) (*Quiz, error) {
+	if definition.Adaptive == nil {
+		return nil, fmt.Errorf("quiz definition %s is not adaptive", definition.Id)
+	}
+	if definition.Status != PUBLISHED {
+		return nil, ErrDefinitionNotPublished
+	}
+
+	q := NewQuizWithOptions(id, nil, opts)
+	q.follow(definition)
+	q.candidates = candidates
+
+	start := definition.Adaptive.StartDifficulty
+	if start == 0 {
+		lowest, highest := q.difficultyRange()
+		start = (lowest + highest) / 2
+	}
+	if !q.pick(start, 0) {
+		return nil, fmt.Errorf("quiz definition %s has no questions to choose from", definition.Id)
+	}
+	return q, nil
+}
+
+// difficultyRange returns the lowest and highest difficulty of the
+// candidates.
+func (q *Quiz) difficultyRange() (int, int) {
+	lowest, highest := 0, 0
+	for i, candidate := range q.candidates {
+		difficulty := candidate.GetDifficulty()
+		if i == 0 || difficulty < lowest {
+			lowest = difficulty
+		}
+		if i == 0 || difficulty > highest {
+			highest = difficulty
+		}
+	}
+	return lowest, highest
+}
+
+// stepRand returns the random source of the adaptive pick at step. Each
+// pick draws from its own source derived from the attempt's seed, so an
+// attempt restored from storage picks as it would have if it had never been
+// stored.
+func (q *Quiz) stepRand(step int) *rand.Rand {
+	return NewRand(q.seed + int64(step) + 1)
+}
+
+// pick adds the unused candidate whose difficulty is closest to target to
+// the attempt and records the choice. It returns false if every candidate
+// has been used.
+func (q *Quiz) pick(target, streak int) bool {
+	used := make(map[string]bool, len(q.questions))
+	for _, question := range q.questions {
+		used[question.GetID()] = true
+	}
+
+	var closest []Questioner
+	distance := math.MaxInt
+	for _, candidate := range q.candidates {
+		if used[candidate.GetID()] {
+			continue
+		}
+		d := candidate.GetDifficulty() - target
+		if d < 0 {
+			d = -d
+		}
+		if d < distance {
+			closest, distance = nil, d
+		}
+		if d == distance {
+			closest = append(closest, candidate)
+		}
+	}
+	if len(closest) == 0 {
+		return false
+	}
+
+	r := q.stepRand(len(q.trace))
+	question := closest[r.Intn(len(closest))]
+	q.questions = append(q.questions, question)
+	if q.settings.ShuffleAnswers {
+		for len(q.optionOrders) < len(q.questions)-1 {
+			q.optionOrders = append(q.optionOrders, nil)
+		}
+		var order []int
+		if shuffler, ok := question.(OptionShuffler); ok {
+			order = shuffler.ShuffleOptions(r)
+		}
+		q.optionOrders = append(q.optionOrders, order)
+	}
+
+	estimate, _ := abilityEstimate(append(q.targets(), target))
+	q.trace = append(q.trace, AdaptiveStep{
+		QuestionID: question.GetID(),
+		Target:     target,
+		Difficulty: question.GetDifficulty(),
+		Streak:     streak,
+		Estimate:   estimate,
+	})
+	return true
+}
+
+// adapt moves the difficulty of an adaptive attempt after its current
+// question and picks the next question, unless the stop rule ends the
+// attempt. It reports whether a question was added.
+func (q *Quiz) adapt() bool {
+	if len(q.trace) == 0 {
+		return false
+	}
+	policy := q.definition.Adaptive
+	last := q.trace[len(q.trace)-1]
+	level, streak := last.Target, last.Streak
+
+	latest := q.latestResult(q.currentIndex)
+	switch {
+	case latest != nil && latest.Pending:
+	case latest != nil && latest.Correct:
+		streak = max(streak, 0) + 1
+		if streak >= atLeastOne(policy.StepUpAfter) {
+			level += atLeastOne(policy.Step)
+			streak = 0
+		}
+	default:
+		streak = min(streak, 0) - 1
+		if -streak >= atLeastOne(policy.StepDownAfter) {
+			level -= atLeastOne(policy.Step)
+			streak = 0
+		}
+	}
+	lowest, highest := q.difficultyRange()
+	level = min(max(level, lowest), highest)
+
+	if len(q.questions) >= policy.Stop.MaxQuestions {
+		return false
+	}
+	if policy.Stop.Precision > 0 && len(q.questions) >= policy.Stop.MinQuestions {
+		if _, stdErr := abilityEstimate(append(q.targets(), level)); stdErr <= policy.Stop.Precision {
+			return false
+		}
+	}
+	return q.pick(level, streak)
+}
+
+// targets returns the difficulty the policy asked for at each step.
+func (q *Quiz) targets() []int {
+	levels := make([]int, len(q.trace))
+	for i, step := range q.trace {
+		levels[i] = step.Target
+	}
+	return levels
+}
+
+// abilityEstimate estimates the taker's ability from the difficulty levels
+// an adaptive attempt visited: the mean of the levels at which it changed
+// direction, and the standard error of that mean. Until the difficulty has
+// turned twice the estimate is the latest level and the error is infinite.
+func abilityEstimate(levels []int) (float64, float64) {
+	if len(levels) == 0 {
+		return 0, math.Inf(1)
+	}
+
+	var reversals []float64
+	direction := 0
+	for i := 1; i < len(levels); i++ {
+		change := levels[i] - levels[i-1]
+		if change == 0 {
+			continue
+		}
+		next := 1
+		if change < 0 {
+			next = -1
+		}
+		if direction != 0 && next != direction {
+			reversals = append(reversals, float64(levels[i-1]))
+		}
+		direction = next
+	}
+	if len(reversals) < 2 {
+		return float64(levels[len(levels)-1]), math.Inf(1)
+	}
+
+	var sum float64
+	for _, level := range reversals {
+		sum += level
+	}
+	mean := sum / float64(len(reversals))
+	var squares float64
+	for _, level := range reversals {
+		squares += (level - mean) * (level - mean)
+	}
+	stdDev := math.Sqrt(squares / float64(len(reversals)-1))
+	return mean, stdDev / math.Sqrt(float64(len(reversals)))
+}
+
+// IsAdaptive reports whether the attempt picks its questions as it is
+// answered.
+func (q *Quiz) IsAdaptive() bool {
+	return q.definition.Adaptive != nil
+}
+
+// AbilityEstimate returns the difficulty level an adaptive attempt estimates
+// the taker to be at.
+func (q *Quiz) AbilityEstimate() float64 {
+	estimate, _ := abilityEstimate(q.targets())
+	return estimate
+}
+
+// GetAdaptiveTrace returns how each question of an adaptive attempt was
+// chosen, in the order they were presented.
+func (q *Quiz) GetAdaptiveTrace() []AdaptiveStep {
+	return q.trace
+}
+
+// GetAdaptiveCandidates returns the questions an adaptive attempt picks its
+// questions from. Which questions they are is fixed when it starts.
+func (q *Quiz) GetAdaptiveCandidates() []Questioner {
+	return q.candidates
+}
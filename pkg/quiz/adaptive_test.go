@@ -0,0 +1,237 @@
+package quiz
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// adaptiveCandidates returns two true/false questions at each difficulty from
+// 1 to 5, all answered with "true".
+func adaptiveCandidates() []Questioner {
+	var candidates []Questioner
+	for difficulty := 1; difficulty <= 5; difficulty++ {
+		for _, suffix := range []string{"a", "b"} {
+			candidates = append(candidates, &TrueFalse{
+				Id:         fmt.Sprintf("d%d%s", difficulty, suffix),
+				Prompt:     fmt.Sprintf("Level %d statement", difficulty),
+				Difficulty: difficulty,
+				Answer:     true,
+			})
+		}
+	}
+	return candidates
+}
+
+func newAdaptiveAttempt(t *testing.T, policy AdaptivePolicy) *Quiz {
+	t.Helper()
+	d := NewQuizDefinition("def1", "Adaptive", nil)
+	d.Adaptive = &policy
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	quiz, err := NewAdaptiveAttempt("a1", d, adaptiveCandidates(), Options{Seed: 7})
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	return quiz
+}
+
+func targets(quiz *Quiz) []int {
+	var levels []int
+	for _, step := range quiz.GetAdaptiveTrace() {
+		levels = append(levels, step.Target)
+	}
+	return levels
+}
+
+func pickedIDs(quiz *Quiz) []string {
+	var ids []string
+	for _, question := range quiz.GetQuestions() {
+		ids = append(ids, question.GetID())
+	}
+	return ids
+}
+
+func TestAdaptiveDifficulty(t *testing.T) {
+	quiz := newAdaptiveAttempt(t, AdaptivePolicy{
+		StartDifficulty: 3,
+		StepUpAfter:     2,
+		Stop:            StopRule{MaxQuestions: 6},
+	})
+	if !quiz.IsAdaptive() || quiz.AmountOfQuestions() != 1 {
+		t.Fatalf("Expected an adaptive attempt with 1 question, got %d", quiz.AmountOfQuestions())
+	}
+
+	// Two correct answers step up, a miss steps down, a skip counts as a miss
+	for _, answer := range []string{"true", "true", "false", "true", "true"} {
+		quiz.SubmitAnswer(answer)
+		if !quiz.NextQuestion() {
+			t.Fatalf("Expected another question after %d", quiz.AmountOfQuestions())
+		}
+	}
+	if want := []int{3, 3, 4, 3, 3, 4}; !reflect.DeepEqual(targets(quiz), want) {
+		t.Errorf("Expected targets %v, got %v", want, targets(quiz))
+	}
+	for i, step := range quiz.GetAdaptiveTrace() {
+		question := quiz.GetQuestions()[i]
+		if step.QuestionID != question.GetID() || step.Difficulty != question.GetDifficulty() {
+			t.Errorf("Expected step %d to record question %s, got %+v", i, question.GetID(), step)
+		}
+	}
+	// Both level 3 questions were used, so the closest one is picked instead
+	if step := quiz.GetAdaptiveTrace()[3]; step.Difficulty != 2 && step.Difficulty != 4 {
+		t.Errorf("Expected a question next to level 3, got difficulty %d", step.Difficulty)
+	}
+	if step := quiz.GetAdaptiveTrace()[1]; step.Streak != 1 {
+		t.Errorf("Expected the second question to follow a streak of 1, got %d", step.Streak)
+	}
+
+	// The stop rule ends the attempt after six questions
+	quiz.SubmitAnswer("true")
+	if quiz.NextQuestion() {
+		t.Errorf("Expected the attempt to stop after 6 questions")
+	}
+	if !quiz.IsCompleted() || quiz.AmountOfQuestions() != 6 {
+		t.Errorf("Expected a completed attempt with 6 questions, got %d", quiz.AmountOfQuestions())
+	}
+}
+
+func TestAdaptiveStopsOnPrecision(t *testing.T) {
+	quiz := newAdaptiveAttempt(t, AdaptivePolicy{
+		StartDifficulty: 3,
+		Stop:            StopRule{MaxQuestions: 10, MinQuestions: 3, Precision: 0.5},
+	})
+	for _, answer := range []string{"true", "false", "true"} {
+		quiz.SubmitAnswer(answer)
+		quiz.NextQuestion()
+	}
+	if !quiz.IsCompleted() || quiz.AmountOfQuestions() != 3 {
+		t.Errorf("Expected the attempt to stop after 3 questions, got %d", quiz.AmountOfQuestions())
+	}
+	if want := []int{3, 4, 3}; !reflect.DeepEqual(targets(quiz), want) {
+		t.Errorf("Expected targets %v, got %v", want, targets(quiz))
+	}
+
+	mean, stdErr := abilityEstimate([]int{3, 4, 3, 4})
+	if mean != 3.5 || stdErr != 0.5 {
+		t.Errorf("Expected estimate 3.5 with error 0.5, got %v and %v", mean, stdErr)
+	}
+}
+
+func TestAdaptiveStaysInRange(t *testing.T) {
+	quiz := newAdaptiveAttempt(t, AdaptivePolicy{Stop: StopRule{MaxQuestions: 20}})
+	if got := quiz.GetAdaptiveTrace()[0].Target; got != 3 {
+		t.Errorf("Expected to start in the middle at 3, got %d", got)
+	}
+	for quiz.NextQuestion() {
+	}
+	// Every question was skipped, so the attempt ran out of candidates
+	if quiz.AmountOfQuestions() != 10 {
+		t.Errorf("Expected all 10 candidates to be used, got %d", quiz.AmountOfQuestions())
+	}
+	for _, level := range targets(quiz) {
+		if level < 1 {
+			t.Errorf("Expected targets to stay at or above 1, got %v", targets(quiz))
+			break
+		}
+	}
+}
+
+func TestAdaptiveRestore(t *testing.T) {
+	policy := AdaptivePolicy{StartDifficulty: 2, Stop: StopRule{MaxQuestions: 4}}
+	quiz := newAdaptiveAttempt(t, policy)
+	quiz.SubmitAnswer("true")
+	quiz.NextQuestion()
+
+	restored := RestoreQuiz(quiz.State(), Options{})
+	restored.SubmitAnswer("true")
+	if !restored.NextQuestion() {
+		t.Fatalf("Expected the restored attempt to pick another question")
+	}
+	if want := []int{2, 3, 4}; !reflect.DeepEqual(targets(restored), want) {
+		t.Errorf("Expected targets %v, got %v", want, targets(restored))
+	}
+}
+
+func TestAdaptiveRestorePicksAsContinuousRun(t *testing.T) {
+	d := NewQuizDefinition("def1", "Adaptive", nil)
+	d.Adaptive = &AdaptivePolicy{StartDifficulty: 1, Stop: StopRule{MaxQuestions: 8}}
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	// Six questions at each level leave the attempt several to choose from
+	var candidates []Questioner
+	for difficulty := 1; difficulty <= 3; difficulty++ {
+		for i := 0; i < 6; i++ {
+			candidates = append(candidates, &TrueFalse{
+				Id:         fmt.Sprintf("d%d-%d", difficulty, i),
+				Prompt:     fmt.Sprintf("Level %d statement", difficulty),
+				Difficulty: difficulty,
+				Answer:     true,
+			})
+		}
+	}
+	start := func() *Quiz {
+		quiz, err := NewAdaptiveAttempt("a1", d, candidates, Options{Seed: 7})
+		if err != nil {
+			t.Fatalf("Failed to start attempt: %v", err)
+		}
+		return quiz
+	}
+	answers := []string{"true", "false", "true", "true", "false", "true", "true"}
+
+	continuous := start()
+	for _, answer := range answers {
+		continuous.SubmitAnswer(answer)
+		continuous.NextQuestion()
+	}
+
+	// Store and restore the attempt after every answer
+	interrupted := start()
+	for _, answer := range answers {
+		interrupted.SubmitAnswer(answer)
+		interrupted = RestoreQuiz(interrupted.State(), Options{})
+		interrupted.NextQuestion()
+	}
+
+	if got, want := pickedIDs(interrupted), pickedIDs(continuous); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the restored attempt to pick %v, got %v", want, got)
+	}
+	if !reflect.DeepEqual(interrupted.GetAdaptiveTrace(), continuous.GetAdaptiveTrace()) {
+		t.Errorf("Expected trace %+v, got %+v", continuous.GetAdaptiveTrace(), interrupted.GetAdaptiveTrace())
+	}
+}
+
+func TestAdaptiveValidation(t *testing.T) {
+	d := NewQuizDefinition("def1", "Adaptive", nil)
+	d.Adaptive = &AdaptivePolicy{}
+	if err := d.Validate(); err == nil {
+		t.Errorf("Expected an error for a policy without a stop rule")
+	}
+
+	d.Adaptive.Stop.MaxQuestions = 5
+	if err := d.Validate(); err != nil {
+		t.Errorf("Expected the definition to validate, got %v", err)
+	}
+
+	d.QuestionIDs = []string{"q1"}
+	if err := d.Validate(); err == nil {
+		t.Errorf("Expected an error for an adaptive definition with questions")
+	}
+
+	d.QuestionIDs = nil
+	d.Navigation = FREE_NAVIGATION
+	if err := d.Validate(); err == nil {
+		t.Errorf("Expected an error for an adaptive definition with free navigation")
+	}
+
+	d.Navigation = LINEAR_NAVIGATION
+	d.Publish()
+	if _, err := NewAttempt("a1", d, nil); err == nil {
+		t.Errorf("Expected an error starting an adaptive definition as a fixed attempt")
+	}
+	if _, err := NewAdaptiveAttempt("a1", d, nil, Options{}); err == nil {
+		t.Errorf("Expected an error without candidates")
+	}
+}
@@ -0,0 +1,47 @@
+package quiz
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AssetRef refers to a media file kept outside the question store, written
+// as "scheme://location", e.g. "file://images/heart.png" or
+// "s3://bucket/heart.png". The scheme selects the AssetResolver used to open it.
+type AssetRef string
+
+// Scheme returns the part of the reference before "://".
+func (a AssetRef) Scheme() string {
+	scheme, _, found := strings.Cut(string(a), "://")
+	if !found {
+		return ""
+	}
+	return scheme
+}
+
+// Location returns the part of the reference after "://".
+func (a AssetRef) Location() string {
+	_, location, found := strings.Cut(string(a), "://")
+	if !found {
+		return string(a)
+	}
+	return location
+}
+
+// AssetResolver opens the assets for one reference scheme.
+type AssetResolver interface {
+	Open(ref AssetRef) (io.ReadCloser, error)
+}
+
+// AssetResolvers maps reference schemes to the resolvers that handle them.
+type AssetResolvers map[string]AssetResolver
+
+// Open opens ref with the resolver registered for its scheme.
+func (r AssetResolvers) Open(ref AssetRef) (io.ReadCloser, error) {
+	resolver, ok := r[ref.Scheme()]
+	if !ok {
+		return nil, fmt.Errorf("no asset resolver for scheme %q", ref.Scheme())
+	}
+	return resolver.Open(ref)
+}
@@ -0,0 +1,46 @@
+package quiz
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+type memoryResolver map[string]string
+
+func (m memoryResolver) Open(ref AssetRef) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(m[ref.Location()])), nil
+}
+
+func TestAssetRef(t *testing.T) {
+	ref := AssetRef("s3://bucket/heart.png")
+	if ref.Scheme() != "s3" {
+		t.Errorf("Expected scheme 's3', got '%s'", ref.Scheme())
+	}
+	if ref.Location() != "bucket/heart.png" {
+		t.Errorf("Expected location 'bucket/heart.png', got '%s'", ref.Location())
+	}
+
+	bare := AssetRef("heart.png")
+	if bare.Scheme() != "" || bare.Location() != "heart.png" {
+		t.Errorf("Expected no scheme and location 'heart.png', got '%s' and '%s'", bare.Scheme(), bare.Location())
+	}
+}
+
+func TestAssetResolvers(t *testing.T) {
+	resolvers := AssetResolvers{"mem": memoryResolver{"heart.png": "image data"}}
+
+	r, err := resolvers.Open("mem://heart.png")
+	if err != nil {
+		t.Fatalf("Failed to open asset: %v", err)
+	}
+	defer r.Close()
+	data, _ := io.ReadAll(r)
+	if string(data) != "image data" {
+		t.Errorf("Expected 'image data', got '%s'", data)
+	}
+
+	if _, err := resolvers.Open("file://heart.png"); err == nil {
+		t.Error("Expected error for unregistered scheme")
+	}
+}
@@ -0,0 +1,87 @@
+package quiz
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock tells an attempt the current time. It is replaced in tests so time
+// limits can be checked without waiting.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the time from the operating system.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a Clock that only moves when told to. It is safe for
+// concurrent use.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a fake clock showing now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to now.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Options control where an attempt reads the time and its random numbers
+// from. The zero value uses the system clock and a seed taken from it.
+type Options struct {
+	Clock Clock
+	// Seed seeds the attempt's random source, which decides shuffles and
+	// pool draws. Attempts with the same seed and questions are shuffled and
+	// drawn the same way, so they can be replayed. Zero picks a seed from
+	// the clock.
+	Seed int64
+}
+
+// clock returns the configured clock or the system clock.
+func (o Options) clock() Clock {
+	if o.Clock == nil {
+		return systemClock{}
+	}
+	return o.Clock
+}
+
+// seed returns the configured seed or one derived from the clock.
+func (o Options) seed() int64 {
+	if o.Seed != 0 {
+		return o.Seed
+	}
+	if seed := o.clock().Now().UnixNano(); seed != 0 {
+		return seed
+	}
+	return 1
+}
+
+// NewRand returns the random source an attempt with the given seed uses.
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,67 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, clock.Now())
+	}
+
+	clock.Advance(90 * time.Second)
+	if want := start.Add(90 * time.Second); !clock.Now().Equal(want) {
+		t.Errorf("Expected %v, got %v", want, clock.Now())
+	}
+
+	clock.Set(start)
+	if !clock.Now().Equal(start) {
+		t.Errorf("Expected %v, got %v", start, clock.Now())
+	}
+}
+
+func TestOptionsClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	quiz := NewQuizWithOptions("q1", createTestQuestions(), Options{Clock: clock})
+
+	if !quiz.GetStartTime().Equal(clock.Now()) {
+		t.Errorf("Expected start time %v, got %v", clock.Now(), quiz.GetStartTime())
+	}
+
+	clock.Advance(7 * time.Second)
+	quiz.SubmitAnswer("4")
+	if taken := quiz.GetQuestionHistory()[0].TimeTaken; taken != 7*time.Second {
+		t.Errorf("Expected time taken 7s, got %v", taken)
+	}
+}
+
+func TestOptionsSeed(t *testing.T) {
+	first := NewQuizWithOptions("q1", createTestQuestions(), Options{Seed: 42})
+	second := NewQuizWithOptions("q2", createTestQuestions(), Options{Seed: 42})
+
+	if first.GetSeed() != 42 {
+		t.Errorf("Expected seed 42, got %d", first.GetSeed())
+	}
+	for i := 0; i < 10; i++ {
+		if a, b := first.rand.Int63(), second.rand.Int63(); a != b {
+			t.Fatalf("Expected attempts with the same seed to draw the same numbers, got %d and %d", a, b)
+		}
+	}
+
+	// Without a seed one is picked from the clock
+	clock := NewFakeClock(time.Unix(0, 12345))
+	quiz := NewQuizWithOptions("q3", createTestQuestions(), Options{Clock: clock})
+	if quiz.GetSeed() != 12345 {
+		t.Errorf("Expected seed 12345, got %d", quiz.GetSeed())
+	}
+
+	// Restoring an attempt keeps its seed
+	restored := RestoreQuiz(first.State(), Options{})
+	if restored.GetSeed() != 42 {
+		t.Errorf("Expected restored seed 42, got %d", restored.GetSeed())
+	}
+}
@@ -0,0 +1,181 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// placeholderPattern matches blanks in a Cloze template, e.g. "{{capital}}".
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// ClozeBlank describes one blank of a Cloze question. A blank with Options is
+// shown as a dropdown and must be answered with one of them; a blank without
+// Options takes free text.
+type ClozeBlank struct {
+	Name          string   `json:"name"`
+	Options       []string `json:"options"`
+	CorrectAnswer string   `json:"correctAnswer"`
+	CaseSensitive bool     `json:"caseSensitive"`
+}
+
+// EncodeBlanks formats the values chosen for each blank as an answer string.
+func EncodeBlanks(values map[string]string) string {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseBlanks parses an answer string produced by EncodeBlanks.
+func ParseBlanks(answer string) (map[string]string, error) {
+	values := make(map[string]string)
+	if strings.TrimSpace(answer) == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(answer), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Cloze presents PromptTemplate with named placeholders such as "{{verb}}"
+// that the user must fill in. It covers both the dropdown (Select Missing)
+// and the drag-and-drop into text variants: for the latter, WordBank lists
+// the items that can be dropped and each item can be used once. Answers map
+// blank names to values and are encoded with EncodeBlanks.
+type Cloze struct {
+	Id             string        `json:"id"`
+	PromptTemplate string        `json:"promptTemplate"`
+	Blanks         []ClozeBlank  `json:"blanks"`
+	WordBank       []string      `json:"wordBank"`
+	Difficulty     int           `json:"difficulty"`
+	Grading        GradingMode   `json:"grading"`
+	Hint           string        `json:"hint"`
+	Hints          []string      `json:"hints"`
+	TimeLimit      time.Duration `json:"timeLimit"`
+}
+
+func (c *Cloze) GetPrompt() string {
+	return c.PromptTemplate
+}
+
+func (c *Cloze) GetID() string {
+	return c.Id
+}
+
+func (c *Cloze) CheckAnswer(answer string) bool {
+	return len(c.Blanks) > 0 && c.correctBlanks(answer) == len(c.Blanks)
+}
+
+// PartialScore returns the fraction of blanks filled in correctly. In
+// ALL_OR_NOTHING mode only a fully correct answer scores.
+func (c *Cloze) PartialScore(answer string) float64 {
+	if len(c.Blanks) == 0 {
+		return 0
+	}
+	correct := c.correctBlanks(answer)
+	if c.Grading != PARTIAL_CREDIT {
+		if correct == len(c.Blanks) {
+			return 1
+		}
+		return 0
+	}
+	return float64(correct) / float64(len(c.Blanks))
+}
+
+// Evaluate grades the answer and reports how many blanks were correct when it
+// is not fully correct.
+func (c *Cloze) Evaluate(answer Answer) GradeResult {
+	result := checkResult(c, answer)
+	if !result.IsCorrect {
+		result.Feedback = fmt.Sprintf("%d of %d blanks are correct", c.correctBlanks(answer.Encode()), len(c.Blanks))
+	}
+	return result
+}
+
+func (c *Cloze) GetDifficulty() int {
+	return c.Difficulty
+}
+
+func (c *Cloze) GetTimeLimit() time.Duration {
+	return c.TimeLimit
+}
+
+func (c *Cloze) GetHints() []string {
+	return hintList(c.Hint, c.Hints)
+}
+
+// Placeholders returns the blank names used in PromptTemplate in the order
+// they appear.
+func (c *Cloze) Placeholders() []string {
+	matches := placeholderPattern.FindAllStringSubmatch(c.PromptTemplate, -1)
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// correctBlanks counts the blanks answered correctly.
+func (c *Cloze) correctBlanks(answer string) int {
+	values, err := ParseBlanks(answer)
+	if err != nil {
+		return 0
+	}
+
+	// Each word bank item can only be dropped into one blank
+	bank := slices.Clone(c.WordBank)
+
+	correct := 0
+	for _, blank := range c.Blanks {
+		value, ok := values[blank.Name]
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(c.WordBank) > 0 {
+			var taken bool
+			if bank, taken = blank.takeFromBank(bank, value); !taken {
+				continue
+			}
+		}
+		if len(blank.Options) > 0 && !slices.Contains(blank.Options, value) {
+			continue
+		}
+		if blank.matches(value) {
+			correct++
+		}
+	}
+	return correct
+}
+
+func (b ClozeBlank) matches(value string) bool {
+	return b.equal(value, strings.TrimSpace(b.CorrectAnswer))
+}
+
+// equal compares two values the way the blank compares answers.
+func (b ClozeBlank) equal(value, other string) bool {
+	if b.CaseSensitive {
+		return value == other
+	}
+	return strings.EqualFold(value, other)
+}
+
+// takeFromBank removes the word bank item the blank's value was dropped
+// from, preferring an item in the same case. It reports false if no
+// remaining item matches the value.
+func (b ClozeBlank) takeFromBank(bank []string, value string) ([]string, bool) {
+	i := slices.Index(bank, value)
+	if i < 0 {
+		i = slices.IndexFunc(bank, func(item string) bool { return b.equal(value, item) })
+	}
+	if i < 0 {
+		return bank, false
+	}
+	return slices.Delete(bank, i, i+1), true
+}
@@ -0,0 +1,117 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func createTestCloze() *Cloze {
+	return &Cloze{
+		Id:             "c1",
+		PromptTemplate: "Je {{verb}} au {{place}} chaque {{ day }}.",
+		Blanks: []ClozeBlank{
+			{Name: "verb", Options: []string{"vais", "va", "allons"}, CorrectAnswer: "vais"},
+			{Name: "place", CorrectAnswer: "marché"},
+			{Name: "day", CorrectAnswer: "Lundi", CaseSensitive: true},
+		},
+		Difficulty: 3,
+		Hint:       "Think of the verb aller",
+		TimeLimit:  90 * time.Second,
+	}
+}
+
+func TestCloze(t *testing.T) {
+	c := createTestCloze()
+
+	// Test getters
+	if c.GetID() != "c1" {
+		t.Errorf("Expected ID 'c1', got '%s'", c.GetID())
+	}
+	if c.GetPrompt() != "Je {{verb}} au {{place}} chaque {{ day }}." {
+		t.Errorf("Expected prompt template, got '%s'", c.GetPrompt())
+	}
+	if c.GetDifficulty() != 3 {
+		t.Errorf("Expected difficulty 3, got %d", c.GetDifficulty())
+	}
+	if c.GetTimeLimit() != 90*time.Second {
+		t.Errorf("Expected time limit 90s, got %v", c.GetTimeLimit())
+	}
+
+	// Test Placeholders
+	names := c.Placeholders()
+	if len(names) != 3 || names[0] != "verb" || names[1] != "place" || names[2] != "day" {
+		t.Errorf("Expected placeholders [verb place day], got %v", names)
+	}
+
+	// Test CheckAnswer
+	correct := EncodeBlanks(map[string]string{"verb": "vais", "place": " Marché ", "day": "Lundi"})
+	if !c.CheckAnswer(correct) {
+		t.Error("Expected correct answer to return true")
+	}
+	wrongCase := EncodeBlanks(map[string]string{"verb": "vais", "place": "marché", "day": "lundi"})
+	if c.CheckAnswer(wrongCase) {
+		t.Error("Expected case sensitive blank to reject 'lundi'")
+	}
+	notAnOption := EncodeBlanks(map[string]string{"verb": "Vais", "place": "marché", "day": "Lundi"})
+	if c.CheckAnswer(notAnOption) {
+		t.Error("Expected dropdown blank to reject a value that is not an option")
+	}
+	if c.CheckAnswer("not json") {
+		t.Error("Expected unparsable answer to return false")
+	}
+}
+
+func TestClozePartialScore(t *testing.T) {
+	c := createTestCloze()
+	answer := EncodeBlanks(map[string]string{"verb": "va", "place": "marché"})
+
+	// Test all-or-nothing scoring is the default
+	if score := c.PartialScore(answer); score != 0 {
+		t.Errorf("Expected score 0 in all-or-nothing mode, got %v", score)
+	}
+
+	// Test credit per blank
+	c.Grading = PARTIAL_CREDIT
+	if score := c.PartialScore(answer); score != 1.0/3.0 {
+		t.Errorf("Expected score 1/3, got %v", score)
+	}
+}
+
+func TestClozeWordBank(t *testing.T) {
+	c := &Cloze{
+		Id:             "c2",
+		PromptTemplate: "The {{animal}} chased the {{other}}.",
+		Blanks: []ClozeBlank{
+			{Name: "animal", CorrectAnswer: "cat"},
+			{Name: "other", CorrectAnswer: "cat"},
+		},
+		WordBank: []string{"cat", "dog"},
+		Grading:  PARTIAL_CREDIT,
+	}
+
+	// Each item in the word bank can only be used once
+	if score := c.PartialScore(EncodeBlanks(map[string]string{"animal": "cat", "other": "cat"})); score != 0.5 {
+		t.Errorf("Expected score 0.5 when reusing a word bank item, got %v", score)
+	}
+
+	c.WordBank = append(c.WordBank, "cat")
+	if !c.CheckAnswer(EncodeBlanks(map[string]string{"animal": "cat", "other": "cat"})) {
+		t.Error("Expected answer to be correct when the word bank has two copies")
+	}
+	if c.CheckAnswer(EncodeBlanks(map[string]string{"animal": "cat", "other": "mouse"})) {
+		t.Error("Expected value outside the word bank to be incorrect")
+	}
+
+	// Case-insensitive blanks find word bank items in any case
+	if !c.CheckAnswer(EncodeBlanks(map[string]string{"animal": "Cat", "other": "CAT"})) {
+		t.Error("Expected word bank items to match case-insensitive blanks in any case")
+	}
+	c.Blanks[0].CaseSensitive = true
+	c.WordBank = []string{"Cat", "cat"}
+	if c.CheckAnswer(EncodeBlanks(map[string]string{"animal": "Cat", "other": "cat"})) {
+		t.Error("Expected 'Cat' to be incorrect for a case-sensitive blank")
+	}
+	if !c.CheckAnswer(EncodeBlanks(map[string]string{"animal": "cat", "other": "Cat"})) {
+		t.Error("Expected each blank to take the word bank item in its own case")
+	}
+}
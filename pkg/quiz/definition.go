@@ -0,0 +1,478 @@
+package quiz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrDefinitionNotPublished is returned when starting an attempt of a quiz
+// definition that is still a draft or has been archived.
+var ErrDefinitionNotPublished = errors.New("quiz definition is not published")
+
+// DefinitionStatus is the lifecycle state of a QuizDefinition.
+type DefinitionStatus string
+
+const (
+	DRAFT     DefinitionStatus = "DRAFT"
+	PUBLISHED DefinitionStatus = "PUBLISHED"
+	ARCHIVED  DefinitionStatus = "ARCHIVED"
+)
+
+// ScoringMethod names how the answers of an attempt are turned into a score.
+type ScoringMethod string
+
+const (
+	// DIFFICULTY_WEIGHTED awards each question up to its difficulty in
+	// points. It is the method used when none is set.
+	DIFFICULTY_WEIGHTED ScoringMethod = "DIFFICULTY_WEIGHTED"
+	// POINTS_PER_QUESTION awards every question the same number of points.
+	POINTS_PER_QUESTION ScoringMethod = "POINTS_PER_QUESTION"
+	// NEGATIVE_MARKING deducts points for wrong answers.
+	NEGATIVE_MARKING ScoringMethod = "NEGATIVE_MARKING"
+	// TIME_BONUS awards extra points for correct answers given quickly.
+	TIME_BONUS ScoringMethod = "TIME_BONUS"
+	// CUSTOM_POINTS awards each question the points set for it.
+	CUSTOM_POINTS ScoringMethod = "CUSTOM_POINTS"
+)
+
+// LatePolicy decides what happens to answers given after a question's time limit.
+type LatePolicy string
+
+const (
+	// ZERO_CREDIT_LATE records late answers without awarding points. It is
+	// the policy used when none is set.
+	ZERO_CREDIT_LATE LatePolicy = "ZERO_CREDIT_LATE"
+	// REJECT_LATE refuses late answers with ErrQuestionExpired.
+	REJECT_LATE LatePolicy = "REJECT_LATE"
+)
+
+// NavigationMode decides how users move between the questions of an attempt.
+type NavigationMode string
+
+const (
+	// LINEAR_NAVIGATION only moves forward with NextQuestion and finishes
+	// the attempt after the last question. It is the mode used when none is
+	// set.
+	LINEAR_NAVIGATION NavigationMode = "LINEAR_NAVIGATION"
+	// FREE_NAVIGATION lets users go back, skip questions and flag them for
+	// review. The attempt is finished with SubmitAll.
+	FREE_NAVIGATION NavigationMode = "FREE_NAVIGATION"
+)
+
+// QuizDefinition is the blueprint of a quiz: its content and the rules that
+// apply to every attempt of it. Questions are referenced by ID in the order
+// they are presented. PassingScore is the percentage of the available points
+// needed to pass, with 0 meaning every attempt passes. A zero TimeLimit means
+// there is no limit.
+//
+// MaxTries is how many times each question may be answered until it is
+// answered correctly; 0 and 1 both allow a single try. RetryDecay holds the
+// share of the points awarded on each try, e.g. 1, 0.5, 0.25. Tries beyond
+// the end of RetryDecay use its last value, and without RetryDecay every try
+// earns full points. LatePolicy applies to answers given after a question's
+// own time limit. Navigation decides whether users may move freely between
+// questions, and HintPenalty what revealing a hint costs.
+//
+// ScoringMethod names the strategy that turns grades into points. Scoring
+// may hold a configured strategy of that method; without it the built-in
+// strategy's defaults apply. In JSON, Scoring is encoded as the settings of
+// the strategy named by ScoringMethod, so only built-in strategies can be
+// encoded.
+//
+// Besides its own questions a definition may draw questions from Pools each
+// time an attempt is started; drawn questions follow the definition's own.
+// With MinimizeOverlap set, questions the takers saw in earlier attempts are
+// only drawn once a pool has no others left. An Adaptive definition has no
+// questions or pools of its own; its attempts pick each question as the
+// previous one is answered.
+//
+// AttemptID is only set on the implicit definition of an attempt started
+// from questions with NewQuiz. Such a definition belongs to that attempt
+// alone: its ID is derived from the attempt's so it cannot clash with a
+// stored definition, and stores keep it out of their listings.
+type QuizDefinition struct {
+	Id               string           `json:"id"`
+	AttemptID        string           `json:"attemptId"`
+	Title            string           `json:"title"`
+	Description      string           `json:"description"`
+	Instructions     string           `json:"instructions"`
+	QuestionIDs      []string         `json:"questionIds"`
+	Pools            []QuestionPool   `json:"pools"`
+	MinimizeOverlap  bool             `json:"minimizeOverlap"`
+	Adaptive         *AdaptivePolicy  `json:"adaptive"`
+	TimeLimit        time.Duration    `json:"timeLimit"`
+	PassingScore     float64          `json:"passingScore"`
+	ScoringMethod    ScoringMethod    `json:"scoringMethod"`
+	Scoring          ScoringStrategy  `json:"-"`
+	ShuffleQuestions bool             `json:"shuffleQuestions"`
+	ShuffleAnswers   bool             `json:"shuffleAnswers"`
+	MaxTries         int              `json:"maxTries"`
+	RetryDecay       []float64        `json:"retryDecay"`
+	LatePolicy       LatePolicy       `json:"latePolicy"`
+	Navigation       NavigationMode   `json:"navigation"`
+	HintPenalty      HintPenalty      `json:"hintPenalty"`
+	Status           DefinitionStatus `json:"status"`
+	CreatedAt        time.Time        `json:"createdAt"`
+	UpdatedAt        time.Time        `json:"updatedAt"`
+
+	clock Clock
+}
+
+// definitionFields has the fields of QuizDefinition without its JSON methods.
+type definitionFields QuizDefinition
+
+// definitionJSON is how a QuizDefinition is encoded in JSON.
+type definitionJSON struct {
+	*definitionFields
+	ScoringConfig json.RawMessage `json:"scoringConfig,omitempty"`
+}
+
+// MarshalJSON encodes the definition with the settings of its scoring
+// strategy under "scoringConfig" and the strategy's method as its
+// ScoringMethod.
+func (d *QuizDefinition) MarshalJSON() ([]byte, error) {
+	fields := definitionFields(*d)
+	encoded := definitionJSON{definitionFields: &fields}
+	if d.Scoring != nil {
+		fields.ScoringMethod = d.Scoring.Method()
+		if NewScoringStrategy(d.Scoring.Method()) == nil {
+			return nil, fmt.Errorf("scoring strategy %s cannot be encoded", d.Scoring.Method())
+		}
+		config, err := json.Marshal(d.Scoring)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal scoring strategy: %v", err)
+		}
+		encoded.ScoringConfig = config
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes a definition encoded by MarshalJSON, rebuilding its
+// scoring strategy from the method and settings.
+func (d *QuizDefinition) UnmarshalJSON(data []byte) error {
+	decoded := definitionJSON{definitionFields: (*definitionFields)(d)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	d.Scoring = nil
+	if len(decoded.ScoringConfig) == 0 {
+		return nil
+	}
+	s := NewScoringStrategy(d.ScoringMethod)
+	if s == nil {
+		return fmt.Errorf("unknown scoring method %s", d.ScoringMethod)
+	}
+	if err := json.Unmarshal(decoded.ScoringConfig, s); err != nil {
+		return fmt.Errorf("failed to unmarshal scoring strategy: %v", err)
+	}
+	d.Scoring = s
+	return nil
+}
+
+// NewQuizDefinition creates a draft definition scored by difficulty.
+func NewQuizDefinition(id, title string, questionIDs []string) *QuizDefinition {
+	return NewQuizDefinitionWithOptions(id, title, questionIDs, Options{})
+}
+
+// NewQuizDefinitionWithOptions creates a draft definition like
+// NewQuizDefinition, reading the time from the clock configured by opts
+// when it is created, published or archived.
+func NewQuizDefinitionWithOptions(id, title string, questionIDs []string, opts Options) *QuizDefinition {
+	clock := opts.clock()
+	now := clock.Now()
+	return &QuizDefinition{
+		Id:            id,
+		Title:         title,
+		QuestionIDs:   questionIDs,
+		ScoringMethod: DIFFICULTY_WEIGHTED,
+		Status:        DRAFT,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		clock:         clock,
+	}
+}
+
+// SetClock makes the definition read the time from clock when it is
+// published or archived, e.g. after it was loaded from a store.
+func (d *QuizDefinition) SetClock(clock Clock) {
+	d.clock = clock
+}
+
+// now returns the time on the definition's clock, or the system time if it
+// has none.
+func (d *QuizDefinition) now() time.Time {
+	if d.clock == nil {
+		return time.Now()
+	}
+	return d.clock.Now()
+}
+
+// Validate checks that the definition can be published.
+func (d *QuizDefinition) Validate() error {
+	if d.Id == "" {
+		return errors.New("quiz definition has no ID")
+	}
+	if d.Title == "" {
+		return fmt.Errorf("quiz definition %s has no title", d.Id)
+	}
+	if d.Adaptive != nil {
+		if len(d.QuestionIDs) > 0 || len(d.Pools) > 0 {
+			return fmt.Errorf("adaptive quiz definition %s must not list questions or pools", d.Id)
+		}
+		if d.Navigation == FREE_NAVIGATION {
+			return fmt.Errorf("adaptive quiz definition %s cannot be navigated freely", d.Id)
+		}
+		if err := d.Adaptive.Validate(); err != nil {
+			return err
+		}
+	} else if len(d.QuestionIDs) == 0 && len(d.Pools) == 0 {
+		return fmt.Errorf("quiz definition %s has no questions", d.Id)
+	}
+	poolIDs := make(map[string]bool, len(d.Pools))
+	for _, pool := range d.Pools {
+		if err := pool.Validate(); err != nil {
+			return err
+		}
+		if poolIDs[pool.Id] {
+			return fmt.Errorf("quiz definition %s has more than one pool %s", d.Id, pool.Id)
+		}
+		poolIDs[pool.Id] = true
+	}
+	if d.TimeLimit < 0 {
+		return fmt.Errorf("quiz definition %s has a negative time limit", d.Id)
+	}
+	if d.PassingScore < 0 || d.PassingScore > 100 {
+		return fmt.Errorf("passing score must be between 0 and 100, got %v", d.PassingScore)
+	}
+	if d.MaxTries < 0 {
+		return fmt.Errorf("quiz definition %s has a negative number of tries", d.Id)
+	}
+	for _, credit := range d.RetryDecay {
+		if credit < 0 || credit > 1 {
+			return fmt.Errorf("retry credit must be between 0 and 1, got %v", credit)
+		}
+	}
+	if err := d.HintPenalty.Validate(); err != nil {
+		return err
+	}
+	return d.validateScoring()
+}
+
+// validateScoring checks that the definition names a scoring method and that
+// its strategy is configured correctly.
+func (d *QuizDefinition) validateScoring() error {
+	if d.Scoring == nil {
+		if NewScoringStrategy(d.ScoringMethod) == nil {
+			return fmt.Errorf("unknown scoring method %s", d.ScoringMethod)
+		}
+		return nil
+	}
+	if d.ScoringMethod != "" && d.ScoringMethod != d.Scoring.Method() {
+		return fmt.Errorf("quiz definition %s uses scoring method %s but its strategy is %s", d.Id, d.ScoringMethod, d.Scoring.Method())
+	}
+	if v, ok := d.Scoring.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// SetScoring makes the definition score its attempts with s.
+func (d *QuizDefinition) SetScoring(s ScoringStrategy) {
+	d.Scoring = s
+	d.ScoringMethod = s.Method()
+}
+
+// Strategy returns the strategy that scores attempts of the definition:
+// Scoring if it is set, or else the built-in strategy named by
+// ScoringMethod. Unknown methods are scored by difficulty.
+func (d *QuizDefinition) Strategy() ScoringStrategy {
+	if d.Scoring != nil {
+		return d.Scoring
+	}
+	if s := NewScoringStrategy(d.ScoringMethod); s != nil {
+		return s
+	}
+	return &DifficultyWeighted{}
+}
+
+// AttemptSettings are the rules of a definition that its attempts follow,
+// as described on QuizDefinition. An attempt copies them when it starts, so
+// editing the definition later does not change attempts in progress.
+type AttemptSettings struct {
+	TimeLimit      time.Duration  `json:"timeLimit"`
+	PassingScore   float64        `json:"passingScore"`
+	ShuffleAnswers bool           `json:"shuffleAnswers"`
+	MaxTries       int            `json:"maxTries"`
+	RetryDecay     []float64      `json:"retryDecay"`
+	LatePolicy     LatePolicy     `json:"latePolicy"`
+	Navigation     NavigationMode `json:"navigation"`
+	HintPenalty    HintPenalty    `json:"hintPenalty"`
+}
+
+// Settings returns a copy of the rules the definition's attempts follow.
+func (d *QuizDefinition) Settings() AttemptSettings {
+	return AttemptSettings{
+		TimeLimit:      d.TimeLimit,
+		PassingScore:   d.PassingScore,
+		ShuffleAnswers: d.ShuffleAnswers,
+		MaxTries:       d.MaxTries,
+		RetryDecay:     slices.Clone(d.RetryDecay),
+		LatePolicy:     d.LatePolicy,
+		Navigation:     d.Navigation,
+		HintPenalty:    d.HintPenalty,
+	}
+}
+
+// AllowedTries returns how many times each question may be answered.
+func (d *QuizDefinition) AllowedTries() int {
+	return d.Settings().AllowedTries()
+}
+
+// TryCredit returns the share of the points awarded on the given try,
+// counting from 1.
+func (d *QuizDefinition) TryCredit(try int) float64 {
+	return d.Settings().TryCredit(try)
+}
+
+// AllowedTries returns how many times each question may be answered.
+func (s AttemptSettings) AllowedTries() int {
+	if s.MaxTries < 1 {
+		return 1
+	}
+	return s.MaxTries
+}
+
+// TryCredit returns the share of the points awarded on the given try,
+// counting from 1.
+func (s AttemptSettings) TryCredit(try int) float64 {
+	if len(s.RetryDecay) == 0 || try < 1 {
+		return 1
+	}
+	if try > len(s.RetryDecay) {
+		return s.RetryDecay[len(s.RetryDecay)-1]
+	}
+	return s.RetryDecay[try-1]
+}
+
+// Publish validates the definition and makes it available for attempts.
+func (d *QuizDefinition) Publish() error {
+	if d.Status == ARCHIVED {
+		return fmt.Errorf("quiz definition %s is archived", d.Id)
+	}
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	d.Status = PUBLISHED
+	d.UpdatedAt = d.now()
+	return nil
+}
+
+// Archive retires the definition. Existing attempts are kept but no new
+// attempts can be started.
+func (d *QuizDefinition) Archive() {
+	d.Status = ARCHIVED
+	d.UpdatedAt = d.now()
+}
+
+// adHocPrefix starts the IDs of the implicit definitions of attempts started
+// with NewQuiz.
+const adHocPrefix = "attempt:"
+
+// adHocDefinition describes a quiz built directly from questions with NewQuiz.
+func adHocDefinition(id string, questions []Questioner, opts Options) *QuizDefinition {
+	ids := make([]string, len(questions))
+	for i, question := range questions {
+		ids[i] = question.GetID()
+	}
+	d := NewQuizDefinitionWithOptions(adHocPrefix+id, id, ids, opts)
+	d.AttemptID = id
+	d.Status = PUBLISHED
+	return d
+}
+
+// IsAdHoc reports whether the definition is the implicit definition of a
+// single attempt started with NewQuiz.
+func (d *QuizDefinition) IsAdHoc() bool {
+	return d.AttemptID != ""
+}
+
+// NewAttempt starts an attempt of a published definition. Questions must be
+// the definition's questions, in the order they are referenced. They and
+// their options are shuffled if the definition asks for it; answers are
+// given against the presented order and graded against the stored one.
+func NewAttempt(id string, definition *QuizDefinition, questions []Questioner) (*Quiz, error) {
+	return NewAttemptWithOptions(id, definition, questions, Options{})
+}
+
+// NewAttemptWithOptions starts an attempt like NewAttempt, reading the time
+// and random numbers as configured by opts.
+func NewAttemptWithOptions(id string, definition *QuizDefinition, questions []Questioner, opts Options) (*Quiz, error) {
+	if len(definition.Pools) > 0 {
+		return nil, fmt.Errorf("quiz definition %s draws questions from pools", definition.Id)
+	}
+	if err := checkQuestions(definition, questions); err != nil {
+		return nil, err
+	}
+
+	q := NewQuizWithOptions(id, questions, opts)
+	q.follow(definition)
+	q.shuffle()
+	return q, nil
+}
+
+// NewDrawnAttempt starts an attempt of a published definition that draws
+// questions from pools. Questions must be the definition's own questions as
+// for NewAttempt, and candidates the questions matching each of its pools.
+// Previous lists the questions the takers saw in earlier attempts, which
+// are drawn last if the definition minimizes overlap. The draw uses the
+// attempt's random source, so the same seed draws the same questions.
+func NewDrawnAttempt(id string, definition *QuizDefinition, questions []Questioner, candidates [][]Questioner, previous []string, opts Options) (*Quiz, error) {
+	if err := checkQuestions(definition, questions); err != nil {
+		return nil, err
+	}
+
+	exclude := make(map[string]bool, len(questions))
+	for _, question := range questions {
+		exclude[question.GetID()] = true
+	}
+	seen := make(map[string]bool, len(previous))
+	if definition.MinimizeOverlap {
+		for _, questionID := range previous {
+			seen[questionID] = true
+		}
+	}
+
+	q := NewQuizWithOptions(id, questions, opts)
+	q.follow(definition)
+	drawn, poolIDs, err := drawQuestions(q.rand, definition.Pools, candidates, exclude, seen)
+	if err != nil {
+		return nil, err
+	}
+	q.questions = append(append([]Questioner{}, questions...), drawn...)
+	q.questionPools = append(make([]string, len(questions)), poolIDs...)
+	q.shuffle()
+	return q, nil
+}
+
+// checkQuestions checks that an attempt of definition can be started with
+// the given questions.
+func checkQuestions(definition *QuizDefinition, questions []Questioner) error {
+	if definition.Status != PUBLISHED {
+		return ErrDefinitionNotPublished
+	}
+	if definition.Adaptive != nil {
+		return fmt.Errorf("quiz definition %s picks its questions adaptively", definition.Id)
+	}
+	if len(questions) != len(definition.QuestionIDs) {
+		return fmt.Errorf("quiz definition %s has %d questions, got %d", definition.Id, len(definition.QuestionIDs), len(questions))
+	}
+	for i, question := range questions {
+		if question.GetID() != definition.QuestionIDs[i] {
+			return fmt.Errorf("expected question %s at position %d, got %s", definition.QuestionIDs[i], i, question.GetID())
+		}
+	}
+	return nil
+}
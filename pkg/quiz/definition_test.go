@@ -0,0 +1,229 @@
+package quiz
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuizDefinitionPublish(t *testing.T) {
+	d := NewQuizDefinition("def1", "Arithmetic", []string{"mc1", "tf1", "fi1"})
+	if d.Status != DRAFT {
+		t.Errorf("Expected status DRAFT, got %s", d.Status)
+	}
+	if d.ScoringMethod != DIFFICULTY_WEIGHTED {
+		t.Errorf("Expected scoring method DIFFICULTY_WEIGHTED, got %s", d.ScoringMethod)
+	}
+
+	d.PassingScore = 120
+	if err := d.Publish(); err == nil {
+		t.Error("Expected a passing score above 100 to be rejected")
+	}
+	d.PassingScore = 60
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if d.Status != PUBLISHED {
+		t.Errorf("Expected status PUBLISHED, got %s", d.Status)
+	}
+
+	d.Archive()
+	if d.Status != ARCHIVED {
+		t.Errorf("Expected status ARCHIVED, got %s", d.Status)
+	}
+	if err := d.Publish(); err == nil {
+		t.Error("Expected an archived definition not to be published again")
+	}
+
+	empty := NewQuizDefinition("def2", "Empty", nil)
+	if err := empty.Publish(); err == nil {
+		t.Error("Expected a definition without questions to be rejected")
+	}
+}
+
+func TestQuizDefinitionClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	d := NewQuizDefinitionWithOptions("def1", "Arithmetic", []string{"mc1"}, Options{Clock: clock})
+	if !d.CreatedAt.Equal(start) || !d.UpdatedAt.Equal(start) {
+		t.Errorf("Expected the definition to be created at %v, got %v and %v", start, d.CreatedAt, d.UpdatedAt)
+	}
+
+	clock.Advance(time.Hour)
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if !d.UpdatedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected the definition to be published at %v, got %v", start.Add(time.Hour), d.UpdatedAt)
+	}
+
+	// A definition loaded elsewhere can be given a clock
+	later := NewFakeClock(start.Add(48 * time.Hour))
+	d.SetClock(later)
+	d.Archive()
+	if !d.UpdatedAt.Equal(later.Now()) {
+		t.Errorf("Expected the definition to be archived at %v, got %v", later.Now(), d.UpdatedAt)
+	}
+}
+
+func TestNewAttempt(t *testing.T) {
+	questions := createTestQuestions()
+	d := NewQuizDefinition("def1", "Arithmetic", []string{"mc1", "tf1", "fi1"})
+	d.TimeLimit = 5 * time.Minute
+
+	if _, err := NewAttempt("a1", d, questions); err != ErrDefinitionNotPublished {
+		t.Errorf("Expected ErrDefinitionNotPublished, got %v", err)
+	}
+
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	if _, err := NewAttempt("a1", d, questions[:2]); err == nil {
+		t.Error("Expected missing questions to be rejected")
+	}
+	if _, err := NewAttempt("a1", d, []Questioner{questions[1], questions[0], questions[2]}); err == nil {
+		t.Error("Expected questions out of order to be rejected")
+	}
+
+	// The same definition can be attempted more than once
+	first, err := NewAttempt("a1", d, questions)
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	second, err := NewAttempt("a2", d, questions)
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+
+	first.SubmitAnswer("4")
+	if second.GetScore() != 0 {
+		t.Errorf("Expected attempts to keep separate scores, got %v", second.GetScore())
+	}
+	if first.GetDefinition() != d || second.GetDefinition() != d {
+		t.Error("Expected both attempts to reference the definition")
+	}
+}
+
+func TestAttemptKeepsSettings(t *testing.T) {
+	d := NewQuizDefinition("def1", "Arithmetic", []string{"mc1", "tf1", "fi1"})
+	d.TimeLimit = 5 * time.Minute
+	d.MaxTries = 2
+	d.RetryDecay = []float64{1, 0.5}
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	quiz, err := NewAttempt("a1", d, createTestQuestions())
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+
+	// Editing the definition only affects attempts started afterwards
+	d.TimeLimit = time.Minute
+	d.MaxTries = 1
+	d.RetryDecay[1] = 0
+	d.Navigation = FREE_NAVIGATION
+	d.PassingScore = 90
+	d.HintPenalty = HintPenalty{Kind: FIXED_PENALTY, Amount: 1}
+
+	want := AttemptSettings{TimeLimit: 5 * time.Minute, MaxTries: 2, RetryDecay: []float64{1, 0.5}}
+	if got := quiz.GetSettings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected settings %+v, got %+v", want, got)
+	}
+	if quiz.RemainingTries() != 2 {
+		t.Errorf("Expected 2 tries, got %d", quiz.RemainingTries())
+	}
+	if err := quiz.GoTo(1); err != ErrLinearNavigation {
+		t.Errorf("Expected ErrLinearNavigation, got %v", err)
+	}
+
+	restored := RestoreQuiz(quiz.State(), Options{})
+	if got := restored.GetSettings(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected restored settings %+v, got %+v", want, got)
+	}
+}
+
+func TestNewQuizDefinition(t *testing.T) {
+	q := NewQuiz("quiz1", createTestQuestions())
+
+	d := q.GetDefinition()
+	if d == nil {
+		t.Fatal("Expected quiz to have a definition")
+	}
+	if !d.IsAdHoc() || d.AttemptID != "quiz1" || d.Status != PUBLISHED {
+		t.Errorf("Expected published definition of attempt 'quiz1', got one of '%s' with status %s", d.AttemptID, d.Status)
+	}
+	if d.Id == "quiz1" {
+		t.Error("Expected the implicit definition not to share the attempt's ID")
+	}
+	if len(d.QuestionIDs) != 3 || d.QuestionIDs[2] != "fi1" {
+		t.Errorf("Expected question IDs [mc1 tf1 fi1], got %v", d.QuestionIDs)
+	}
+}
+
+func TestQuizDefinitionJSON(t *testing.T) {
+	d := NewQuizDefinition("def1", "Arithmetic", []string{"mc1"})
+	d.Scoring = &NegativeMarking{Points: 2, Penalty: 0.5, FloorAtZero: true}
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Failed to marshal definition: %v", err)
+	}
+	var decoded QuizDefinition
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal definition: %v", err)
+	}
+	if decoded.Id != "def1" || decoded.ScoringMethod != NEGATIVE_MARKING {
+		t.Errorf("Expected definition def1 with NEGATIVE_MARKING, got %s with %s", decoded.Id, decoded.ScoringMethod)
+	}
+	scoring, ok := decoded.Scoring.(*NegativeMarking)
+	if !ok || *scoring != (NegativeMarking{Points: 2, Penalty: 0.5, FloorAtZero: true}) {
+		t.Errorf("Expected the negative marking settings to be kept, got %+v", decoded.Scoring)
+	}
+
+	// Definitions without a configured strategy decode without one
+	d.Scoring = nil
+	d.ScoringMethod = POINTS_PER_QUESTION
+	data, err = json.Marshal(d)
+	if err != nil {
+		t.Fatalf("Failed to marshal definition: %v", err)
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal definition: %v", err)
+	}
+	if decoded.Scoring != nil || decoded.ScoringMethod != POINTS_PER_QUESTION {
+		t.Errorf("Expected POINTS_PER_QUESTION without settings, got %s with %+v", decoded.ScoringMethod, decoded.Scoring)
+	}
+}
+
+func TestQuizDefinitionTries(t *testing.T) {
+	d := NewQuizDefinition("def1", "Arithmetic", []string{"mc1"})
+	if d.AllowedTries() != 1 {
+		t.Errorf("Expected 1 try by default, got %d", d.AllowedTries())
+	}
+	if d.TryCredit(3) != 1 {
+		t.Errorf("Expected full credit without decay, got %v", d.TryCredit(3))
+	}
+
+	d.MaxTries = 4
+	d.RetryDecay = []float64{1, 0.5, 0.25}
+	tests := []struct {
+		try    int
+		credit float64
+	}{
+		{1, 1},
+		{2, 0.5},
+		{3, 0.25},
+		{4, 0.25},
+	}
+	for _, tt := range tests {
+		if credit := d.TryCredit(tt.try); credit != tt.credit {
+			t.Errorf("Expected credit %v for try %d, got %v", tt.credit, tt.try, credit)
+		}
+	}
+
+	d.RetryDecay = []float64{1, 1.5}
+	if err := d.Validate(); err == nil {
+		t.Error("Expected a retry credit above 1 to be rejected")
+	}
+}
@@ -0,0 +1,107 @@
+package quiz
+
+// Answer is a user's response to a question. Encode returns the string form
+// understood by the question's CheckAnswer, so any Answer can be graded by
+// any question that accepts that form.
+type Answer interface {
+	Encode() string
+}
+
+// TextAnswer is a typed or selected answer, used by MultiChoice, TrueFalse,
+// FillIn and Numeric questions and for the asset references of recorded
+// responses.
+type TextAnswer string
+
+func (a TextAnswer) Encode() string {
+	return string(a)
+}
+
+// IndicesAnswer is the set of options selected for a MultiResponse question.
+type IndicesAnswer []int
+
+func (a IndicesAnswer) Encode() string {
+	return EncodeIndices(a)
+}
+
+// OrderAnswer is the sequence of item indices chosen for an Ordering question.
+type OrderAnswer []int
+
+func (a OrderAnswer) Encode() string {
+	return EncodeIndices(a)
+}
+
+// PairingsAnswer is the set of pairs made for a Matching question.
+type PairingsAnswer []Pairing
+
+func (a PairingsAnswer) Encode() string {
+	return EncodePairings(a)
+}
+
+// BlanksAnswer maps the blanks of a Cloze question to the values given.
+type BlanksAnswer map[string]string
+
+func (a BlanksAnswer) Encode() string {
+	return EncodeBlanks(a)
+}
+
+// PointsAnswer is the list of clicks made on a Hotspot image.
+type PointsAnswer []Point
+
+func (a PointsAnswer) Encode() string {
+	return EncodePoints(a)
+}
+
+// GradeResult is the outcome of evaluating one answer. MaxScore is the
+// number of points the question is worth: its difficulty, unless the
+// attempt's ScoringStrategy says otherwise. Pending is set when the answer
+// needs manual grading and has not been scored yet.
+type GradeResult struct {
+	IsCorrect    bool
+	ScoreAwarded float64
+	MaxScore     float64
+	Feedback     string
+	Pending      bool
+}
+
+// Fraction returns the share of the available points that was awarded.
+func (g GradeResult) Fraction() float64 {
+	if g.MaxScore <= 0 {
+		if g.IsCorrect {
+			return 1
+		}
+		return 0
+	}
+	return g.ScoreAwarded / g.MaxScore
+}
+
+// Evaluator is implemented by questions that grade answers themselves and can
+// explain the outcome.
+type Evaluator interface {
+	Evaluate(answer Answer) GradeResult
+}
+
+// Evaluate grades answer against q. Questions that are not Evaluators are
+// graded through CheckAnswer, and PartialScore when they implement it.
+func Evaluate(q Questioner, answer Answer) GradeResult {
+	if e, ok := q.(Evaluator); ok {
+		return e.Evaluate(answer)
+	}
+	return checkResult(q, answer)
+}
+
+// checkResult grades answer without asking q to evaluate it itself.
+func checkResult(q Questioner, answer Answer) GradeResult {
+	maxScore := float64(q.GetDifficulty())
+	if needsManualGrading(q) {
+		return GradeResult{MaxScore: maxScore, Pending: true}
+	}
+
+	encoded := answer.Encode()
+	result := GradeResult{IsCorrect: q.CheckAnswer(encoded), MaxScore: maxScore}
+	if p, ok := q.(PartialScorer); ok {
+		result.ScoreAwarded = p.PartialScore(encoded) * maxScore
+	} else if result.IsCorrect {
+		result.ScoreAwarded = maxScore
+	}
+	return result
+}
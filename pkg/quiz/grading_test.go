@@ -0,0 +1,141 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnswerEncode(t *testing.T) {
+	tests := []struct {
+		answer   Answer
+		expected string
+	}{
+		{TextAnswer("Paris"), "Paris"},
+		{IndicesAnswer{0, 2}, "0,2"},
+		{OrderAnswer{2, 0, 1}, "2,0,1"},
+		{PairingsAnswer{{List1Index: 0, List2Index: 1}}, "0:1"},
+		{BlanksAnswer{"verb": "vais"}, `{"verb":"vais"}`},
+		{PointsAnswer{{X: 1, Y: 2.5}}, "1,2.5"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.answer.Encode(); got != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+		}
+	}
+}
+
+func TestEvaluateAdapter(t *testing.T) {
+	mc := &MultiChoice{Id: "mc1", Prompt: "What is 2+2?", Options: []string{"3", "4"}, Difficulty: 2, Answer: "4"}
+
+	result := Evaluate(mc, TextAnswer("4"))
+	if !result.IsCorrect || result.ScoreAwarded != 2 || result.MaxScore != 2 {
+		t.Errorf("Expected full marks for correct answer, got %+v", result)
+	}
+
+	result = Evaluate(mc, TextAnswer("3"))
+	if result.IsCorrect || result.ScoreAwarded != 0 || result.MaxScore != 2 {
+		t.Errorf("Expected no marks for incorrect answer, got %+v", result)
+	}
+
+	essay := &Essay{Id: "e1", Prompt: "Explain recursion", Difficulty: 5}
+	result = Evaluate(essay, TextAnswer("It calls itself"))
+	if !result.Pending || result.MaxScore != 5 {
+		t.Errorf("Expected pending result for essay, got %+v", result)
+	}
+}
+
+func TestEvaluatePartialCredit(t *testing.T) {
+	m := &Matching{
+		Id:         "m1",
+		Prompt:     "Match the capitals",
+		List1Items: []string{"France", "Italy"},
+		List2Items: []string{"Rome", "Paris"},
+		CorrectPairings: []Pairing{
+			{List1Index: 0, List2Index: 1},
+			{List1Index: 1, List2Index: 0},
+		},
+		Difficulty: 4,
+		Grading:    PARTIAL_CREDIT,
+	}
+
+	result := Evaluate(m, PairingsAnswer{{List1Index: 0, List2Index: 1}, {List1Index: 1, List2Index: 1}})
+	if result.IsCorrect {
+		t.Error("Expected partially correct answer not to be correct")
+	}
+	if result.ScoreAwarded != 2 || result.MaxScore != 4 {
+		t.Errorf("Expected 2 of 4 points, got %v of %v", result.ScoreAwarded, result.MaxScore)
+	}
+	if result.Fraction() != 0.5 {
+		t.Errorf("Expected fraction 0.5, got %v", result.Fraction())
+	}
+	if result.Feedback != "1 of 2 pairs are correct" {
+		t.Errorf("Expected feedback '1 of 2 pairs are correct', got '%s'", result.Feedback)
+	}
+
+	result = Evaluate(m, PairingsAnswer{{List1Index: 0, List2Index: 1}, {List1Index: 1, List2Index: 0}})
+	if !result.IsCorrect || result.ScoreAwarded != 4 || result.Feedback != "" {
+		t.Errorf("Expected full marks without feedback, got %+v", result)
+	}
+}
+
+func TestGradeResultFraction(t *testing.T) {
+	if (GradeResult{IsCorrect: true}).Fraction() != 1 {
+		t.Error("Expected correct answer worth no points to have fraction 1")
+	}
+	if (GradeResult{}).Fraction() != 0 {
+		t.Error("Expected incorrect answer worth no points to have fraction 0")
+	}
+	if (GradeResult{ScoreAwarded: 1, MaxScore: 4}).Fraction() != 0.25 {
+		t.Error("Expected fraction 0.25")
+	}
+}
+
+func TestQuizSubmitStructuredAnswer(t *testing.T) {
+	questions := []Questioner{
+		&MultiResponse{
+			Id:                   "mr1",
+			Prompt:               "Which are prime?",
+			Options:              []string{"2", "4", "5", "9"},
+			CorrectAnswerIndices: []int{0, 2},
+			Difficulty:           4,
+			Grading:              PARTIAL_CREDIT,
+			TimeLimit:            30 * time.Second,
+		},
+		&FillIn{Id: "fi1", Prompt: "The capital of France is ___", Difficulty: 3, Answer: "Paris"},
+	}
+	quiz := NewQuiz("quiz1", questions)
+
+	result, err := quiz.Submit(IndicesAnswer{0})
+	if err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+	if result.IsCorrect || result.ScoreAwarded != 2 {
+		t.Errorf("Expected 2 points for a partially correct answer, got %+v", result)
+	}
+	if quiz.GetScore() != 2 {
+		t.Errorf("Expected score 2, got %v", quiz.GetScore())
+	}
+	if quiz.GetCorrectCount() != 0 {
+		t.Errorf("Expected 0 correct answers, got %d", quiz.GetCorrectCount())
+	}
+
+	history := quiz.GetQuestionHistory()
+	if history[0].Grade.ScoreAwarded != 2 || history[0].Grade.Feedback == "" {
+		t.Errorf("Expected grade with feedback in history, got %+v", history[0].Grade)
+	}
+
+	// The string based API keeps working for the original question types
+	quiz.NextQuestion()
+	if !quiz.SubmitAnswer("Paris") {
+		t.Error("Expected 'Paris' to be correct")
+	}
+	if quiz.GetScore() != 5 {
+		t.Errorf("Expected score 5, got %v", quiz.GetScore())
+	}
+
+	quiz.NextQuestion()
+	if _, err := quiz.Submit(TextAnswer("Paris")); err != ErrQuizCompleted {
+		t.Errorf("Expected ErrQuizCompleted, got %v", err)
+	}
+}
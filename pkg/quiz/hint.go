@@ -0,0 +1,115 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoHintsLeft is returned when revealing a hint for a question whose
+// hints have all been revealed.
+var ErrNoHintsLeft = errors.New("no hints left for this question")
+
+// hintList returns a question's hints in the order they are revealed: Hint
+// first, followed by the progressively more revealing Hints.
+func hintList(hint string, hints []string) []string {
+	if hint == "" {
+		return hints
+	}
+	return append([]string{hint}, hints...)
+}
+
+// PenaltyKind names how a HintPenalty is measured.
+type PenaltyKind string
+
+const (
+	// FIXED_PENALTY deducts Amount points for each hint revealed.
+	FIXED_PENALTY PenaltyKind = "FIXED_PENALTY"
+	// PERCENTAGE_PENALTY deducts Amount percent of the question's points for
+	// each hint revealed.
+	PERCENTAGE_PENALTY PenaltyKind = "PERCENTAGE_PENALTY"
+)
+
+// HintPenalty is deducted from a question's score for every hint revealed
+// before it was answered. A question never scores less than zero. The zero
+// value deducts nothing.
+type HintPenalty struct {
+	Kind   PenaltyKind `json:"kind"`
+	Amount float64     `json:"amount"`
+}
+
+// Validate checks that the penalty can be applied.
+func (p HintPenalty) Validate() error {
+	if p.Kind == "" {
+		return nil
+	}
+	if p.Kind != FIXED_PENALTY && p.Kind != PERCENTAGE_PENALTY {
+		return fmt.Errorf("unknown hint penalty kind %s", p.Kind)
+	}
+	if p.Amount < 0 {
+		return fmt.Errorf("hint penalty must not be negative, got %v", p.Amount)
+	}
+	if p.Kind == PERCENTAGE_PENALTY && p.Amount > 100 {
+		return fmt.Errorf("hint penalty percentage must be at most 100, got %v", p.Amount)
+	}
+	return nil
+}
+
+// Apply returns the points awarded for a question worth maxScore that
+// scored points after hintsUsed hints were revealed.
+func (p HintPenalty) Apply(points, maxScore float64, hintsUsed int) float64 {
+	if hintsUsed < 1 {
+		return points
+	}
+	var penalty float64
+	switch p.Kind {
+	case FIXED_PENALTY:
+		penalty = p.Amount * float64(hintsUsed)
+	case PERCENTAGE_PENALTY:
+		penalty = maxScore * p.Amount / 100 * float64(hintsUsed)
+	}
+	return math.Max(points-penalty, 0)
+}
+
+// RevealHint returns the next hint of the current question and records that
+// it was used. The definition's HintPenalty is deducted from the points the
+// question earns when it is answered.
+func (q *Quiz) RevealHint() (string, error) {
+	if q.expire(q.clock.Now()) {
+		return "", ErrQuizExpired
+	}
+	current := q.CurrentQuestion()
+	if q.completed || current == nil {
+		return "", ErrQuizCompleted
+	}
+	if err := q.checkTransition(ANSWERED); err != nil {
+		return "", err
+	}
+	if q.RemainingTries() == 0 {
+		return "", ErrNoTriesLeft
+	}
+
+	hints := current.GetHints()
+	used := q.hintsUsed[q.currentIndex]
+	if used >= len(hints) {
+		return "", ErrNoHintsLeft
+	}
+	q.hintsUsed[q.currentIndex] = used + 1
+	return hints[used], nil
+}
+
+// GetHintsUsed returns how many hints of the question at index have been
+// revealed.
+func (q *Quiz) GetHintsUsed(index int) int {
+	return q.hintsUsed[index]
+}
+
+// RevealedHints returns the hints of the question at index that have been
+// revealed so far.
+func (q *Quiz) RevealedHints(index int) []string {
+	if index < 0 || index >= len(q.questions) {
+		return nil
+	}
+	hints := q.questions[index].GetHints()
+	return hints[:min(q.hintsUsed[index], len(hints))]
+}
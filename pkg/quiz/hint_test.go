@@ -0,0 +1,128 @@
+package quiz
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newHintedAttempt(t *testing.T, penalty HintPenalty) *Quiz {
+	t.Helper()
+	questions := []Questioner{
+		&MultiChoice{
+			Id:         "mc1",
+			Prompt:     "What is 2+2?",
+			Options:    []string{"3", "4", "5"},
+			Difficulty: 4,
+			Answer:     "4",
+			Hint:       "It is even",
+			Hints:      []string{"It is less than 5", "It is 2 squared"},
+		},
+		&TrueFalse{Id: "tf1", Prompt: "The sky is blue", Difficulty: 2, Answer: true},
+	}
+	d := NewQuizDefinition("def1", "Hinted", []string{"mc1", "tf1"})
+	d.HintPenalty = penalty
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	quiz, err := NewAttempt("a1", d, questions)
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	return quiz
+}
+
+func TestGetHints(t *testing.T) {
+	mc := &MultiChoice{Hint: "first", Hints: []string{"second", "third"}}
+	if got := mc.GetHints(); !reflect.DeepEqual(got, []string{"first", "second", "third"}) {
+		t.Errorf("Expected hints [first second third], got %v", got)
+	}
+	if got := (&Essay{}).GetHints(); len(got) != 0 {
+		t.Errorf("Expected no hints, got %v", got)
+	}
+	if got := (&Numeric{Hints: []string{"only"}}).GetHints(); !reflect.DeepEqual(got, []string{"only"}) {
+		t.Errorf("Expected hints [only], got %v", got)
+	}
+}
+
+func TestRevealHint(t *testing.T) {
+	quiz := newHintedAttempt(t, HintPenalty{Kind: FIXED_PENALTY, Amount: 1})
+
+	for _, want := range []string{"It is even", "It is less than 5", "It is 2 squared"} {
+		hint, err := quiz.RevealHint()
+		if err != nil {
+			t.Fatalf("Failed to reveal hint: %v", err)
+		}
+		if hint != want {
+			t.Errorf("Expected hint %q, got %q", want, hint)
+		}
+	}
+	if _, err := quiz.RevealHint(); err != ErrNoHintsLeft {
+		t.Errorf("Expected ErrNoHintsLeft, got %v", err)
+	}
+	if quiz.GetHintsUsed(0) != 3 || len(quiz.RevealedHints(0)) != 3 {
+		t.Errorf("Expected 3 hints used, got %d", quiz.GetHintsUsed(0))
+	}
+
+	result, err := quiz.Submit(TextAnswer("4"))
+	if err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+	if result.ScoreAwarded != 1 {
+		t.Errorf("Expected score 1 after 3 fixed penalties, got %v", result.ScoreAwarded)
+	}
+	if quiz.GetQuestionHistory()[0].HintsUsed != 3 {
+		t.Errorf("Expected the history to record 3 hints, got %d", quiz.GetQuestionHistory()[0].HintsUsed)
+	}
+	if _, err := quiz.RevealHint(); !errors.Is(err, ErrNoTriesLeft) {
+		t.Errorf("Expected ErrNoTriesLeft, got %v", err)
+	}
+
+	// Questions without hints have none to reveal
+	quiz.NextQuestion()
+	if _, err := quiz.RevealHint(); err != ErrNoHintsLeft {
+		t.Errorf("Expected ErrNoHintsLeft, got %v", err)
+	}
+
+	// Regrading keeps the penalty
+	if changed := quiz.Regrade(); changed != 0 {
+		t.Errorf("Expected no regraded results, got %d", changed)
+	}
+}
+
+func TestHintPenalty(t *testing.T) {
+	tests := []struct {
+		penalty   HintPenalty
+		hintsUsed int
+		want      float64
+	}{
+		{HintPenalty{}, 2, 4},
+		{HintPenalty{Kind: FIXED_PENALTY, Amount: 1.5}, 2, 1},
+		{HintPenalty{Kind: FIXED_PENALTY, Amount: 3}, 2, 0},
+		{HintPenalty{Kind: PERCENTAGE_PENALTY, Amount: 25}, 1, 3},
+		{HintPenalty{Kind: PERCENTAGE_PENALTY, Amount: 25}, 0, 4},
+	}
+	for _, tt := range tests {
+		if got := tt.penalty.Apply(4, 4, tt.hintsUsed); got != tt.want {
+			t.Errorf("Expected %v for %+v with %d hints, got %v", tt.want, tt.penalty, tt.hintsUsed, got)
+		}
+	}
+
+	if err := (HintPenalty{Kind: PERCENTAGE_PENALTY, Amount: 150}).Validate(); err == nil {
+		t.Errorf("Expected an error for a penalty above 100%%")
+	}
+	if err := (HintPenalty{Kind: "HALF", Amount: 1}).Validate(); err == nil {
+		t.Errorf("Expected an error for an unknown penalty kind")
+	}
+
+	quiz := newHintedAttempt(t, HintPenalty{Kind: PERCENTAGE_PENALTY, Amount: 50})
+	quiz.RevealHint()
+	quiz.SubmitAnswer("4")
+	if quiz.GetScore() != 2 {
+		t.Errorf("Expected score 2, got %v", quiz.GetScore())
+	}
+	restored := RestoreQuiz(quiz.State(), Options{})
+	if restored.GetHintsUsed(0) != 1 {
+		t.Errorf("Expected restored hints used 1, got %d", restored.GetHintsUsed(0))
+	}
+}
@@ -0,0 +1,229 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Point is a position on a Hotspot image in image coordinates.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// EncodePoints formats click coordinates as an answer string, e.g. "10,20;35.5,8".
+func EncodePoints(points []Point) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = strconv.FormatFloat(p.X, 'g', -1, 64) + "," + strconv.FormatFloat(p.Y, 'g', -1, 64)
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParsePoints parses an answer string produced by EncodePoints.
+func ParsePoints(answer string) ([]Point, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return []Point{}, nil
+	}
+	parts := strings.Split(answer, ";")
+	points := make([]Point, 0, len(parts))
+	for _, part := range parts {
+		xs, ys, found := strings.Cut(part, ",")
+		if !found {
+			return nil, fmt.Errorf("invalid point %q", part)
+		}
+		x, err := strconv.ParseFloat(strings.TrimSpace(xs), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %v", part, err)
+		}
+		y, err := strconv.ParseFloat(strings.TrimSpace(ys), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %v", part, err)
+		}
+		points = append(points, Point{X: x, Y: y})
+	}
+	return points, nil
+}
+
+// ShapeKind selects how the points of an Area are interpreted.
+type ShapeKind string
+
+const (
+	RECTANGLE ShapeKind = "RECTANGLE"
+	CIRCLE    ShapeKind = "CIRCLE"
+	POLYGON   ShapeKind = "POLYGON"
+)
+
+// Area is a region of a Hotspot image. A RECTANGLE is given by two opposite
+// corners, a CIRCLE by its centre and Radius, and a POLYGON by its vertices.
+type Area struct {
+	Shape  ShapeKind `json:"shape"`
+	Points []Point   `json:"points"`
+	Radius float64   `json:"radius,omitempty"`
+}
+
+// Contains reports whether p lies inside or on the edge of the area.
+func (a Area) Contains(p Point) bool {
+	switch a.Shape {
+	case RECTANGLE:
+		if len(a.Points) != 2 {
+			return false
+		}
+		minX, maxX := math.Min(a.Points[0].X, a.Points[1].X), math.Max(a.Points[0].X, a.Points[1].X)
+		minY, maxY := math.Min(a.Points[0].Y, a.Points[1].Y), math.Max(a.Points[0].Y, a.Points[1].Y)
+		return p.X >= minX && p.X <= maxX && p.Y >= minY && p.Y <= maxY
+	case CIRCLE:
+		if len(a.Points) != 1 {
+			return false
+		}
+		return math.Hypot(p.X-a.Points[0].X, p.Y-a.Points[0].Y) <= a.Radius
+	case POLYGON:
+		return polygonContains(a.Points, p)
+	default:
+		return false
+	}
+}
+
+// polygonContains uses ray casting to test whether p is inside the polygon.
+func polygonContains(vertices []Point, p Point) bool {
+	if len(vertices) < 3 {
+		return false
+	}
+	inside := false
+	for i, j := 0, len(vertices)-1; i < len(vertices); j, i = i, i+1 {
+		a, b := vertices[i], vertices[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) &&
+			p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Hotspot shows Image and asks the user to click on every area in
+// CorrectAreas. Answers are click coordinates encoded with EncodePoints. A
+// click outside every correct area counts against the answer.
+type Hotspot struct {
+	Id           string        `json:"id"`
+	Prompt       string        `json:"prompt"`
+	Image        AssetRef      `json:"image"`
+	CorrectAreas []Area        `json:"correctAreas"`
+	Difficulty   int           `json:"difficulty"`
+	Grading      GradingMode   `json:"grading"`
+	Hint         string        `json:"hint"`
+	Hints        []string      `json:"hints"`
+	TimeLimit    time.Duration `json:"timeLimit"`
+}
+
+func (h *Hotspot) GetPrompt() string {
+	return h.Prompt
+}
+
+func (h *Hotspot) GetID() string {
+	return h.Id
+}
+
+func (h *Hotspot) CheckAnswer(answer string) bool {
+	hit, stray, ok := h.clicks(answer)
+	return ok && len(h.CorrectAreas) > 0 && hit == len(h.CorrectAreas) && stray == 0
+}
+
+// PartialScore returns the fraction of correct areas that were clicked, with
+// each stray click counted as an extra missed area. In ALL_OR_NOTHING mode
+// only a fully correct answer scores.
+func (h *Hotspot) PartialScore(answer string) float64 {
+	if h.Grading != PARTIAL_CREDIT {
+		if h.CheckAnswer(answer) {
+			return 1
+		}
+		return 0
+	}
+	hit, stray, ok := h.clicks(answer)
+	if !ok || len(h.CorrectAreas) == 0 {
+		return 0
+	}
+	return float64(hit) / float64(len(h.CorrectAreas)+stray)
+}
+
+// Evaluate grades the answer and reports how many areas were found when it
+// is not fully correct.
+func (h *Hotspot) Evaluate(answer Answer) GradeResult {
+	result := checkResult(h, answer)
+	if !result.IsCorrect {
+		hit, stray, _ := h.clicks(answer.Encode())
+		result.Feedback = fmt.Sprintf("%d of %d areas found", hit, len(h.CorrectAreas))
+		if stray > 0 {
+			result.Feedback += fmt.Sprintf(", %d clicks outside them", stray)
+		}
+	}
+	return result
+}
+
+func (h *Hotspot) GetDifficulty() int {
+	return h.Difficulty
+}
+
+func (h *Hotspot) GetTimeLimit() time.Duration {
+	return h.TimeLimit
+}
+
+func (h *Hotspot) GetHints() []string {
+	return hintList(h.Hint, h.Hints)
+}
+
+// clicks returns how many correct areas were clicked and how many clicks
+// landed outside every correct area. Each click counts toward at most one
+// area, so a single click where areas overlap does not find both of them.
+func (h *Hotspot) clicks(answer string) (int, int, bool) {
+	points, err := ParsePoints(answer)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	// areasOf lists the areas each click lies in
+	areasOf := make([][]int, len(points))
+	stray := 0
+	for c, p := range points {
+		for i, area := range h.CorrectAreas {
+			if area.Contains(p) {
+				areasOf[c] = append(areasOf[c], i)
+			}
+		}
+		if len(areasOf[c]) == 0 {
+			stray++
+		}
+	}
+
+	// Assign clicks to areas so that as many areas as possible are found,
+	// moving an earlier click to another of its areas when that frees one
+	clickOf := make([]int, len(h.CorrectAreas))
+	for i := range clickOf {
+		clickOf[i] = -1
+	}
+	var assign func(c int, visited []bool) bool
+	assign = func(c int, visited []bool) bool {
+		for _, i := range areasOf[c] {
+			if visited[i] {
+				continue
+			}
+			visited[i] = true
+			if clickOf[i] < 0 || assign(clickOf[i], visited) {
+				clickOf[i] = c
+				return true
+			}
+		}
+		return false
+	}
+
+	hit := 0
+	for c := range points {
+		if assign(c, make([]bool, len(h.CorrectAreas))) {
+			hit++
+		}
+	}
+	return hit, stray, true
+}
@@ -0,0 +1,150 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func createTestHotspot() *Hotspot {
+	return &Hotspot{
+		Id:     "h1",
+		Prompt: "Click on the two buttons",
+		Image:  "file://images/dialog.png",
+		CorrectAreas: []Area{
+			{Shape: RECTANGLE, Points: []Point{{X: 10, Y: 10}, {X: 50, Y: 30}}},
+			{Shape: CIRCLE, Points: []Point{{X: 100, Y: 100}}, Radius: 10},
+		},
+		Difficulty: 2,
+		Hint:       "One of them is round",
+		TimeLimit:  20 * time.Second,
+	}
+}
+
+func TestHotspot(t *testing.T) {
+	h := createTestHotspot()
+
+	// Test getters
+	if h.GetID() != "h1" {
+		t.Errorf("Expected ID 'h1', got '%s'", h.GetID())
+	}
+	if h.GetPrompt() != "Click on the two buttons" {
+		t.Errorf("Expected prompt 'Click on the two buttons', got '%s'", h.GetPrompt())
+	}
+	if h.GetDifficulty() != 2 {
+		t.Errorf("Expected difficulty 2, got %d", h.GetDifficulty())
+	}
+	if h.GetTimeLimit() != 20*time.Second {
+		t.Errorf("Expected time limit 20s, got %v", h.GetTimeLimit())
+	}
+
+	// Test CheckAnswer
+	if !h.CheckAnswer("20,20;105,95") {
+		t.Error("Expected clicks in both areas to return true")
+	}
+	if !h.CheckAnswer("50,30;110,100;20,15") {
+		t.Error("Expected clicks on the edges and repeated clicks to return true")
+	}
+	if h.CheckAnswer("20,20") {
+		t.Error("Expected a missed area to return false")
+	}
+	if h.CheckAnswer("20,20;105,95;300,300") {
+		t.Error("Expected a stray click to return false")
+	}
+	if h.CheckAnswer("20;20") {
+		t.Error("Expected unparsable answer to return false")
+	}
+}
+
+func TestHotspotPartialScore(t *testing.T) {
+	h := createTestHotspot()
+
+	// Test all-or-nothing scoring is the default
+	if score := h.PartialScore("20,20"); score != 0 {
+		t.Errorf("Expected score 0 in all-or-nothing mode, got %v", score)
+	}
+
+	// Test credit per area, with stray clicks counting against it
+	h.Grading = PARTIAL_CREDIT
+	if score := h.PartialScore("20,20"); score != 0.5 {
+		t.Errorf("Expected score 0.5, got %v", score)
+	}
+	if score := h.PartialScore("20,20;105,95;300,300"); score != 2.0/3.0 {
+		t.Errorf("Expected score 2/3, got %v", score)
+	}
+}
+
+func TestHotspotOverlappingAreas(t *testing.T) {
+	h := &Hotspot{
+		Id:     "h2",
+		Prompt: "Click on both overlapping panels",
+		CorrectAreas: []Area{
+			{Shape: RECTANGLE, Points: []Point{{X: 0, Y: 0}, {X: 20, Y: 20}}},
+			{Shape: RECTANGLE, Points: []Point{{X: 10, Y: 10}, {X: 30, Y: 30}}},
+		},
+		Grading: PARTIAL_CREDIT,
+	}
+
+	// One click in the overlap finds only one of the areas
+	if h.CheckAnswer("15,15") {
+		t.Error("Expected a single click in the overlap not to find both areas")
+	}
+	if score := h.PartialScore("15,15"); score != 0.5 {
+		t.Errorf("Expected score 0.5, got %v", score)
+	}
+
+	// A click in the overlap can stand for whichever area the others miss
+	if !h.CheckAnswer("15,15;5,5") || !h.CheckAnswer("15,15;25,25") {
+		t.Error("Expected one click in the overlap and one in either area to be correct")
+	}
+}
+
+func TestAreaContains(t *testing.T) {
+	// An L shaped polygon
+	polygon := Area{Shape: POLYGON, Points: []Point{
+		{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 4}, {X: 4, Y: 4}, {X: 4, Y: 10}, {X: 0, Y: 10},
+	}}
+	if !polygon.Contains(Point{X: 2, Y: 8}) {
+		t.Error("Expected point in the vertical bar to be inside")
+	}
+	if !polygon.Contains(Point{X: 8, Y: 2}) {
+		t.Error("Expected point in the horizontal bar to be inside")
+	}
+	if polygon.Contains(Point{X: 8, Y: 8}) {
+		t.Error("Expected point in the notch to be outside")
+	}
+
+	// Rectangle corners may be given in any order
+	rect := Area{Shape: RECTANGLE, Points: []Point{{X: 5, Y: 5}, {X: 0, Y: 0}}}
+	if !rect.Contains(Point{X: 1, Y: 4}) {
+		t.Error("Expected point to be inside rectangle")
+	}
+
+	circle := Area{Shape: CIRCLE, Points: []Point{{X: 0, Y: 0}}, Radius: 5}
+	if !circle.Contains(Point{X: 3, Y: 4}) {
+		t.Error("Expected point on the circle edge to be inside")
+	}
+	if circle.Contains(Point{X: 4, Y: 4}) {
+		t.Error("Expected point outside the circle")
+	}
+
+	malformed := Area{Shape: CIRCLE, Radius: 5}
+	if malformed.Contains(Point{}) {
+		t.Error("Expected area without a centre to contain nothing")
+	}
+}
+
+func TestParsePoints(t *testing.T) {
+	points, err := ParsePoints("1.5,2; 3,-4")
+	if err != nil {
+		t.Fatalf("Failed to parse points: %v", err)
+	}
+	if len(points) != 2 || points[0] != (Point{X: 1.5, Y: 2}) || points[1] != (Point{X: 3, Y: -4}) {
+		t.Errorf("Expected [{1.5 2} {3 -4}], got %v", points)
+	}
+	if EncodePoints(points) != "1.5,2;3,-4" {
+		t.Errorf("Expected '1.5,2;3,-4', got '%s'", EncodePoints(points))
+	}
+	if _, err := ParsePoints("1.5"); err == nil {
+		t.Error("Expected error for point without y coordinate")
+	}
+}
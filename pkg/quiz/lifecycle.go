@@ -0,0 +1,164 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTransition is wrapped by every TransitionError.
+var ErrInvalidTransition = errors.New("invalid quiz status transition")
+
+// TransitionError is returned when an action would move an attempt into a
+// status its current status cannot move to, such as answering after QUIT.
+type TransitionError struct {
+	From QuizStatus
+	To   QuizStatus
+	// Reason is why the attempt cannot stay in its status, such as
+	// ErrNoTriesLeft. It is nil if the statuses never follow each other.
+	Reason error
+}
+
+func (e *TransitionError) Error() string {
+	if e.Reason != nil {
+		return fmt.Sprintf("quiz cannot move from %s to %s: %v", e.From, e.To, e.Reason)
+	}
+	return fmt.Sprintf("quiz cannot move from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() []error {
+	if e.Reason != nil {
+		return []error{ErrInvalidTransition, e.Reason}
+	}
+	return []error{ErrInvalidTransition}
+}
+
+// transitions lists the statuses each status can move to. A SAVED attempt
+// can only be quit or resumed, which returns it to the status it was paused
+// in. FINISHED, QUIT and EXPIRED are final. Staying ANSWERED to retry a
+// wrong answer, or AWAITING_ANSWER to move between unanswered questions, is
+// not listed; checkTransition allows it while the current question has tries
+// left.
+var transitions = map[QuizStatus][]QuizStatus{
+	STARTED:          {AWAITING_ANSWER, ANSWERED, SAVED, QUIT, FINISHED, AWAITING_GRADING, EXPIRED},
+	AWAITING_ANSWER:  {ANSWERED, SAVED, QUIT, FINISHED, AWAITING_GRADING, EXPIRED},
+	ANSWERED:         {AWAITING_ANSWER, SAVED, QUIT, FINISHED, AWAITING_GRADING, EXPIRED},
+	SAVED:            {QUIT},
+	AWAITING_GRADING: {FINISHED},
+	FINISHED:         {},
+	QUIT:             {},
+	EXPIRED:          {},
+}
+
+// CanTransition reports whether an attempt with status s may move to status to.
+func (s QuizStatus) CanTransition(to QuizStatus) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransition returns a TransitionError if the attempt cannot move to
+// status to. An answered or unanswered question keeps its status only while
+// it has tries left.
+func (q *Quiz) checkTransition(to QuizStatus) error {
+	if to == q.status && (to == ANSWERED || to == AWAITING_ANSWER) {
+		if q.RemainingTries() == 0 {
+			return &TransitionError{From: q.status, To: to, Reason: ErrNoTriesLeft}
+		}
+		return nil
+	}
+	if !q.status.CanTransition(to) {
+		return &TransitionError{From: q.status, To: to}
+	}
+	return nil
+}
+
+// Pause saves the attempt for later. While an attempt is paused it cannot
+// be answered or navigated, and neither the quiz's nor the current
+// question's time runs.
+func (q *Quiz) Pause() error {
+	now := q.clock.Now()
+	if q.expire(now) {
+		return ErrQuizExpired
+	}
+	if err := q.checkTransition(SAVED); err != nil {
+		return err
+	}
+	q.status = SAVED
+	q.pausedAt = now
+	return nil
+}
+
+// Resume continues a paused attempt where it was left.
+func (q *Quiz) Resume() error {
+	if q.status != SAVED {
+		return &TransitionError{From: q.status, To: q.resumedStatus()}
+	}
+	paused := q.clock.Now().Sub(q.pausedAt)
+	q.pausedTime += paused
+	q.presentedAt = q.presentedAt.Add(paused)
+	q.pausedAt = time.Time{}
+	q.status = q.resumedStatus()
+	return nil
+}
+
+// resumedStatus returns the status a paused attempt returns to.
+func (q *Quiz) resumedStatus() QuizStatus {
+	switch {
+	case q.latestResult(q.currentIndex) != nil:
+		return ANSWERED
+	case q.currentIndex == 0 && len(q.questionHistory) == 0:
+		return STARTED
+	default:
+		return AWAITING_ANSWER
+	}
+}
+
+// Quit abandons the attempt. Its answers so far are kept but it can no
+// longer be answered.
+func (q *Quiz) Quit() error {
+	now := q.clock.Now()
+	if q.expire(now) {
+		return ErrQuizExpired
+	}
+	if err := q.checkTransition(QUIT); err != nil {
+		return err
+	}
+	q.timeTaken = q.activeTime(now)
+	q.status = QUIT
+	q.pausedAt = time.Time{}
+	return nil
+}
+
+// runningNow returns the time the attempt's timers stand at: now, or when
+// the attempt was paused.
+func (q *Quiz) runningNow() time.Time {
+	if q.status == SAVED {
+		return q.pausedAt
+	}
+	return q.clock.Now()
+}
+
+// activeTime returns how long the attempt has run at now, not counting the
+// time it was paused.
+func (q *Quiz) activeTime(now time.Time) time.Duration {
+	if q.status == SAVED {
+		now = q.pausedAt
+	}
+	return now.Sub(q.startTime) - q.pausedTime
+}
+
+// GetPausedAt returns when the attempt was paused, or the zero time if it is
+// not paused.
+func (q *Quiz) GetPausedAt() time.Time {
+	return q.pausedAt
+}
+
+// GetPausedTime returns how long the attempt was paused before it was last
+// resumed.
+func (q *Quiz) GetPausedTime() time.Duration {
+	return q.pausedTime
+}
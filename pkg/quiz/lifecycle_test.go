@@ -0,0 +1,168 @@
+package quiz
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuizStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to QuizStatus
+		want     bool
+	}{
+		{STARTED, ANSWERED, true},
+		{ANSWERED, ANSWERED, false},
+		{ANSWERED, AWAITING_ANSWER, true},
+		{AWAITING_ANSWER, AWAITING_ANSWER, false},
+		{ANSWERED, SAVED, true},
+		{SAVED, ANSWERED, false},
+		{SAVED, QUIT, true},
+		{SAVED, EXPIRED, false},
+		{SAVED, FINISHED, false},
+		{QUIT, ANSWERED, false},
+		{FINISHED, SAVED, false},
+		{AWAITING_GRADING, FINISHED, true},
+		{EXPIRED, QUIT, false},
+	}
+	for _, tt := range tests {
+		if got := tt.from.CanTransition(tt.to); got != tt.want {
+			t.Errorf("Expected %s to %s to be %v, got %v", tt.from, tt.to, tt.want, got)
+		}
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	quiz, clock := newTimedAttempt(t, func(d *QuizDefinition) {
+		d.TimeLimit = time.Minute
+	})
+
+	clock.Advance(10 * time.Second)
+	if err := quiz.Pause(); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	if quiz.GetStatus() != SAVED {
+		t.Errorf("Expected status SAVED, got %s", quiz.GetStatus())
+	}
+
+	// A paused attempt cannot be answered or moved and its time stands still
+	var transitionErr *TransitionError
+	if _, err := quiz.Submit(TextAnswer("4")); !errors.As(err, &transitionErr) || transitionErr.From != SAVED {
+		t.Errorf("Expected a TransitionError from SAVED, got %v", err)
+	}
+	if quiz.NextQuestion() {
+		t.Errorf("Expected a paused attempt not to move on")
+	}
+	clock.Advance(5 * time.Minute)
+	if quiz.IsCompleted() {
+		t.Errorf("Expected a paused attempt not to expire")
+	}
+	if quiz.GetTimeTaken() != 10*time.Second {
+		t.Errorf("Expected time taken 10s, got %v", quiz.GetTimeTaken())
+	}
+	if remaining, _ := quiz.RemainingTime(); remaining != 50*time.Second {
+		t.Errorf("Expected 50s remaining, got %v", remaining)
+	}
+	if err := quiz.Pause(); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition, got %v", err)
+	}
+
+	if err := quiz.Resume(); err != nil {
+		t.Fatalf("Failed to resume: %v", err)
+	}
+	if quiz.GetStatus() != STARTED {
+		t.Errorf("Expected status STARTED, got %s", quiz.GetStatus())
+	}
+	if err := quiz.Resume(); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition, got %v", err)
+	}
+
+	// The pause does not count towards the question's time either
+	clock.Advance(5 * time.Second)
+	quiz.SubmitAnswer("4")
+	if taken := quiz.GetQuestionHistory()[0].TimeTaken; taken != 15*time.Second {
+		t.Errorf("Expected time taken 15s, got %v", taken)
+	}
+	if quiz.GetStatus() != ANSWERED {
+		t.Errorf("Expected status ANSWERED, got %s", quiz.GetStatus())
+	}
+
+	// The deadline moved by the length of the pause
+	clock.Advance(40 * time.Second)
+	if quiz.CheckExpiry() {
+		t.Errorf("Expected the attempt to be within its time limit")
+	}
+	clock.Advance(10 * time.Second)
+	if !quiz.CheckExpiry() || quiz.GetStatus() != EXPIRED {
+		t.Errorf("Expected status EXPIRED, got %s", quiz.GetStatus())
+	}
+}
+
+func TestQuit(t *testing.T) {
+	quiz, clock := newTimedAttempt(t, func(d *QuizDefinition) {})
+
+	clock.Advance(20 * time.Second)
+	quiz.SubmitAnswer("4")
+	if err := quiz.Quit(); err != nil {
+		t.Fatalf("Failed to quit: %v", err)
+	}
+	if quiz.GetStatus() != QUIT {
+		t.Errorf("Expected status QUIT, got %s", quiz.GetStatus())
+	}
+
+	clock.Advance(time.Minute)
+	if quiz.GetTimeTaken() != 20*time.Second {
+		t.Errorf("Expected time taken 20s, got %v", quiz.GetTimeTaken())
+	}
+	if quiz.GetScore() != 1 {
+		t.Errorf("Expected the answers so far to be kept, got score %v", quiz.GetScore())
+	}
+
+	var transitionErr *TransitionError
+	if _, err := quiz.Submit(TextAnswer("true")); !errors.As(err, &transitionErr) || transitionErr.To != ANSWERED {
+		t.Errorf("Expected a TransitionError to ANSWERED, got %v", err)
+	}
+	if err := quiz.Pause(); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition, got %v", err)
+	}
+	if err := quiz.Quit(); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition, got %v", err)
+	}
+	if quiz.NextQuestion() {
+		t.Errorf("Expected a quit attempt not to move on")
+	}
+
+	// Finished attempts cannot be quit
+	finished, _ := newTimedAttempt(t, func(d *QuizDefinition) {})
+	for finished.NextQuestion() {
+	}
+	if err := finished.Quit(); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestAnsweredQuestionTransitions(t *testing.T) {
+	quiz := newNavigableAttempt(t, FREE_NAVIGATION)
+	quiz.SubmitAnswer("4")
+	if err := quiz.GoTo(1); err != nil {
+		t.Fatalf("Failed to go to question 1: %v", err)
+	}
+	if quiz.GetStatus() != AWAITING_ANSWER {
+		t.Errorf("Expected status AWAITING_ANSWER, got %s", quiz.GetStatus())
+	}
+
+	// Returning to an answered question leaves nothing to answer
+	if err := quiz.GoTo(0); err != nil {
+		t.Fatalf("Failed to go to question 0: %v", err)
+	}
+	if quiz.GetStatus() != ANSWERED {
+		t.Errorf("Expected status ANSWERED, got %s", quiz.GetStatus())
+	}
+	var transitionErr *TransitionError
+	if _, err := quiz.Submit(TextAnswer("4")); !errors.As(err, &transitionErr) || transitionErr.Reason != ErrNoTriesLeft {
+		t.Errorf("Expected a TransitionError for ErrNoTriesLeft, got %v", err)
+	}
+	if err := quiz.Skip(); err != nil {
+		t.Errorf("Expected to move on from an answered question, got %v", err)
+	}
+}
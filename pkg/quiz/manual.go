@@ -0,0 +1,141 @@
+package quiz
+
+import "time"
+
+// ManuallyGraded is implemented by questions whose answers cannot be checked
+// automatically. Their CheckAnswer always returns false; the quiz stores the
+// response as pending until a grader scores it with Quiz.GradeResponse.
+type ManuallyGraded interface {
+	Questioner
+	RequiresManualGrading() bool
+}
+
+// Essay asks for a written answer. MinWords and MaxWords are guidance for the
+// user and the grader, with 0 meaning no limit.
+type Essay struct {
+	Id         string        `json:"id"`
+	Prompt     string        `json:"prompt"`
+	Difficulty int           `json:"difficulty"`
+	Rubric     string        `json:"rubric"`
+	MinWords   int           `json:"minWords"`
+	MaxWords   int           `json:"maxWords"`
+	Hint       string        `json:"hint"`
+	Hints      []string      `json:"hints"`
+	TimeLimit  time.Duration `json:"timeLimit"`
+}
+
+func (e *Essay) GetPrompt() string {
+	return e.Prompt
+}
+
+func (e *Essay) GetID() string {
+	return e.Id
+}
+
+func (e *Essay) CheckAnswer(answer string) bool {
+	return false
+}
+
+func (e *Essay) GetDifficulty() int {
+	return e.Difficulty
+}
+
+func (e *Essay) GetTimeLimit() time.Duration {
+	return e.TimeLimit
+}
+
+func (e *Essay) GetHints() []string {
+	return hintList(e.Hint, e.Hints)
+}
+
+func (e *Essay) RequiresManualGrading() bool {
+	return true
+}
+
+// AudioResponse asks the user to record an answer. The answer is the
+// AssetRef of the uploaded recording.
+type AudioResponse struct {
+	Id          string        `json:"id"`
+	Prompt      string        `json:"prompt"`
+	Difficulty  int           `json:"difficulty"`
+	Rubric      string        `json:"rubric"`
+	MaxDuration time.Duration `json:"maxDuration"`
+	Hint        string        `json:"hint"`
+	Hints       []string      `json:"hints"`
+	TimeLimit   time.Duration `json:"timeLimit"`
+}
+
+func (a *AudioResponse) GetPrompt() string {
+	return a.Prompt
+}
+
+func (a *AudioResponse) GetID() string {
+	return a.Id
+}
+
+func (a *AudioResponse) CheckAnswer(answer string) bool {
+	return false
+}
+
+func (a *AudioResponse) GetDifficulty() int {
+	return a.Difficulty
+}
+
+func (a *AudioResponse) GetTimeLimit() time.Duration {
+	return a.TimeLimit
+}
+
+func (a *AudioResponse) GetHints() []string {
+	return hintList(a.Hint, a.Hints)
+}
+
+func (a *AudioResponse) RequiresManualGrading() bool {
+	return true
+}
+
+// VideoResponse asks the user to record a video. The answer is the AssetRef
+// of the uploaded recording.
+type VideoResponse struct {
+	Id          string        `json:"id"`
+	Prompt      string        `json:"prompt"`
+	Difficulty  int           `json:"difficulty"`
+	Rubric      string        `json:"rubric"`
+	MaxDuration time.Duration `json:"maxDuration"`
+	Hint        string        `json:"hint"`
+	Hints       []string      `json:"hints"`
+	TimeLimit   time.Duration `json:"timeLimit"`
+}
+
+func (v *VideoResponse) GetPrompt() string {
+	return v.Prompt
+}
+
+func (v *VideoResponse) GetID() string {
+	return v.Id
+}
+
+func (v *VideoResponse) CheckAnswer(answer string) bool {
+	return false
+}
+
+func (v *VideoResponse) GetDifficulty() int {
+	return v.Difficulty
+}
+
+func (v *VideoResponse) GetTimeLimit() time.Duration {
+	return v.TimeLimit
+}
+
+func (v *VideoResponse) GetHints() []string {
+	return hintList(v.Hint, v.Hints)
+}
+
+func (v *VideoResponse) RequiresManualGrading() bool {
+	return true
+}
+
+// needsManualGrading reports whether q's answers must be graded by a person.
+func needsManualGrading(q Questioner) bool {
+	m, ok := q.(ManuallyGraded)
+	return ok && m.RequiresManualGrading()
+}
@@ -0,0 +1,126 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManuallyGradedQuestions(t *testing.T) {
+	questions := []Questioner{
+		&Essay{Id: "e1", Prompt: "Describe the water cycle", Difficulty: 5, MinWords: 100, TimeLimit: 10 * time.Minute},
+		&AudioResponse{Id: "a1", Prompt: "Introduce yourself in Spanish", Difficulty: 3, MaxDuration: time.Minute},
+		&VideoResponse{Id: "v1", Prompt: "Demonstrate the recovery position", Difficulty: 4, MaxDuration: 2 * time.Minute},
+	}
+
+	for _, q := range questions {
+		m, ok := q.(ManuallyGraded)
+		if !ok {
+			t.Errorf("Expected %s to be manually graded", q.GetID())
+			continue
+		}
+		if !m.RequiresManualGrading() {
+			t.Errorf("Expected %s to require manual grading", q.GetID())
+		}
+		if q.CheckAnswer("anything") {
+			t.Errorf("Expected %s not to grade answers automatically", q.GetID())
+		}
+	}
+
+	if needsManualGrading(&FillIn{Id: "fi1", Answer: "Paris"}) {
+		t.Error("Expected FillIn not to need manual grading")
+	}
+}
+
+func TestQuizPendingResponses(t *testing.T) {
+	essay := &Essay{Id: "e1", Prompt: "Describe the water cycle", Difficulty: 5}
+	questions := []Questioner{
+		&FillIn{Id: "fi1", Prompt: "The capital of France is ___", Difficulty: 3, Answer: "Paris"},
+		essay,
+	}
+	quiz := NewQuiz("quiz1", questions)
+
+	quiz.SubmitAnswer("Paris")
+	quiz.NextQuestion()
+	if quiz.SubmitAnswer("Water evaporates, condenses and falls as rain.") {
+		t.Error("Expected essay answer not to be graded immediately")
+	}
+	if !quiz.HasPendingGrades() {
+		t.Error("Expected a pending grade")
+	}
+
+	history := quiz.GetQuestionHistory()
+	if !history[1].Pending || history[1].Attempt.Answer != "Water evaporates, condenses and falls as rain." {
+		t.Errorf("Expected pending essay response in history, got %+v", history[1])
+	}
+
+	// Finishing the quiz waits for grading
+	quiz.NextQuestion()
+	if quiz.GetStatus() != AWAITING_GRADING {
+		t.Errorf("Expected status AWAITING_GRADING, got %v", quiz.GetStatus())
+	}
+	if !quiz.IsCompleted() {
+		t.Error("Expected quiz to be completed")
+	}
+
+	// Test invalid grades
+	if err := quiz.GradeResponse(1, 6, ""); err == nil {
+		t.Error("Expected error for points above the question's difficulty")
+	}
+	if err := quiz.GradeResponse(0, 1, ""); err == nil {
+		t.Error("Expected error for question without a pending response")
+	}
+	if err := quiz.GradeResponse(2, 1, ""); err != ErrNoSuchQuestion {
+		t.Errorf("Expected ErrNoSuchQuestion, got %v", err)
+	}
+
+	// Grading the last response finalizes the score
+	if err := quiz.GradeResponse(1, 4, "Missing condensation"); err != nil {
+		t.Fatalf("Failed to grade response: %v", err)
+	}
+	if quiz.GetStatus() != FINISHED {
+		t.Errorf("Expected status FINISHED, got %v", quiz.GetStatus())
+	}
+	if quiz.GetScore() != 7 {
+		t.Errorf("Expected score 7, got %v", quiz.GetScore())
+	}
+	if quiz.GetCorrectCount() != 1 {
+		t.Errorf("Expected 1 correct answer for partial points, got %d", quiz.GetCorrectCount())
+	}
+	if quiz.HasPendingGrades() {
+		t.Error("Expected no pending grades")
+	}
+	if err := quiz.GradeResponse(1, 5, ""); err == nil {
+		t.Error("Expected error when grading a response twice")
+	}
+}
+
+func TestGradeRepeatedQuestion(t *testing.T) {
+	essay := &Essay{Id: "e1", Prompt: "Describe the water cycle", Difficulty: 5}
+	quiz := NewQuiz("quiz1", []Questioner{essay, essay})
+
+	quiz.SubmitAnswer("Water evaporates.")
+	quiz.NextQuestion()
+	quiz.SubmitAnswer("Water evaporates, condenses and falls as rain.")
+	quiz.NextQuestion()
+
+	if err := quiz.GradeResponse(1, 5, "Complete"); err != nil {
+		t.Fatalf("Failed to grade the second occurrence: %v", err)
+	}
+	history := quiz.GetQuestionHistory()
+	if !history[0].Pending || history[1].Pending {
+		t.Errorf("Expected only the first occurrence to be pending, got %v and %v", history[0].Pending, history[1].Pending)
+	}
+	if err := quiz.GradeResponse(1, 5, ""); err == nil {
+		t.Error("Expected error when grading the second occurrence twice")
+	}
+
+	if err := quiz.GradeResponse(0, 2, "Incomplete"); err != nil {
+		t.Fatalf("Failed to grade the first occurrence: %v", err)
+	}
+	if quiz.GetStatus() != FINISHED {
+		t.Errorf("Expected status FINISHED, got %v", quiz.GetStatus())
+	}
+	if quiz.GetScore() != 7 {
+		t.Errorf("Expected score 7, got %v", quiz.GetScore())
+	}
+}
@@ -0,0 +1,157 @@
+package quiz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pairing links an item of the first list of a Matching question to an item
+// of the second list by index.
+type Pairing struct {
+	List1Index int `json:"list1Index"`
+	List2Index int `json:"list2Index"`
+}
+
+// EncodePairings formats pairings as an answer string, e.g. "0:1,1:0".
+func EncodePairings(pairings []Pairing) string {
+	parts := make([]string, len(pairings))
+	for i, p := range pairings {
+		parts[i] = strconv.Itoa(p.List1Index) + ":" + strconv.Itoa(p.List2Index)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParsePairings parses an answer string produced by EncodePairings.
+func ParsePairings(answer string) ([]Pairing, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return []Pairing{}, nil
+	}
+	parts := strings.Split(answer, ",")
+	pairings := make([]Pairing, 0, len(parts))
+	for _, part := range parts {
+		left, right, found := strings.Cut(part, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid pairing %q", part)
+		}
+		l, err := strconv.Atoi(strings.TrimSpace(left))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pairing %q: %v", part, err)
+		}
+		r, err := strconv.Atoi(strings.TrimSpace(right))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pairing %q: %v", part, err)
+		}
+		pairings = append(pairings, Pairing{List1Index: l, List2Index: r})
+	}
+	return pairings, nil
+}
+
+// Matching asks the user to pair each item of List1Items with an item of
+// List2Items. Answers are encoded with EncodePairings. List2Items may contain
+// distractors that belong to no pairing.
+type Matching struct {
+	Id              string        `json:"id"`
+	Prompt          string        `json:"prompt"`
+	List1Items      []string      `json:"list1Items"`
+	List2Items      []string      `json:"list2Items"`
+	CorrectPairings []Pairing     `json:"correctPairings"`
+	Difficulty      int           `json:"difficulty"`
+	Grading         GradingMode   `json:"grading"`
+	Hint            string        `json:"hint"`
+	Hints           []string      `json:"hints"`
+	TimeLimit       time.Duration `json:"timeLimit"`
+}
+
+func (m *Matching) GetPrompt() string {
+	return m.Prompt
+}
+
+func (m *Matching) GetID() string {
+	return m.Id
+}
+
+func (m *Matching) CheckAnswer(answer string) bool {
+	correct, total, ok := m.correctPairs(answer)
+	return ok && correct == len(m.CorrectPairings) && total == correct
+}
+
+// PartialScore returns the fraction of correct pairings found in the answer.
+// In ALL_OR_NOTHING mode only a fully correct answer scores.
+func (m *Matching) PartialScore(answer string) float64 {
+	if m.Grading != PARTIAL_CREDIT {
+		if m.CheckAnswer(answer) {
+			return 1
+		}
+		return 0
+	}
+	if len(m.CorrectPairings) == 0 {
+		return 0
+	}
+	correct, _, ok := m.correctPairs(answer)
+	if !ok {
+		return 0
+	}
+	return float64(correct) / float64(len(m.CorrectPairings))
+}
+
+// Evaluate grades the answer and reports how many pairs were correct when it
+// is not fully correct.
+func (m *Matching) Evaluate(answer Answer) GradeResult {
+	result := checkResult(m, answer)
+	if !result.IsCorrect {
+		correct, _, ok := m.correctPairs(answer.Encode())
+		if ok {
+			result.Feedback = fmt.Sprintf("%d of %d pairs are correct", correct, len(m.CorrectPairings))
+		} else {
+			result.Feedback = "The pairings are not valid for this question"
+		}
+	}
+	return result
+}
+
+func (m *Matching) GetDifficulty() int {
+	return m.Difficulty
+}
+
+func (m *Matching) GetTimeLimit() time.Duration {
+	return m.TimeLimit
+}
+
+func (m *Matching) GetHints() []string {
+	return hintList(m.Hint, m.Hints)
+}
+
+// correctPairs returns how many of the answer's pairings are correct and how
+// many pairings it contains. Answers that reference items outside the lists
+// or pair the same List1 item twice are rejected.
+func (m *Matching) correctPairs(answer string) (int, int, bool) {
+	pairings, err := ParsePairings(answer)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	expected := make(map[int]int, len(m.CorrectPairings))
+	for _, p := range m.CorrectPairings {
+		expected[p.List1Index] = p.List2Index
+	}
+
+	used := make(map[int]bool, len(pairings))
+	correct := 0
+	for _, p := range pairings {
+		if p.List1Index < 0 || p.List1Index >= len(m.List1Items) ||
+			p.List2Index < 0 || p.List2Index >= len(m.List2Items) {
+			return 0, 0, false
+		}
+		if used[p.List1Index] {
+			return 0, 0, false
+		}
+		used[p.List1Index] = true
+		if right, ok := expected[p.List1Index]; ok && right == p.List2Index {
+			correct++
+		}
+	}
+	return correct, len(pairings), true
+}
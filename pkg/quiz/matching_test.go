@@ -0,0 +1,106 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func createTestMatching() *Matching {
+	return &Matching{
+		Id:         "m1",
+		Prompt:     "Match each country to its capital",
+		List1Items: []string{"France", "Germany", "Italy"},
+		List2Items: []string{"Berlin", "Rome", "Paris", "Madrid"},
+		CorrectPairings: []Pairing{
+			{List1Index: 0, List2Index: 2},
+			{List1Index: 1, List2Index: 0},
+			{List1Index: 2, List2Index: 1},
+		},
+		Difficulty: 3,
+		Hint:       "Madrid is a distractor",
+		TimeLimit:  60 * time.Second,
+	}
+}
+
+func TestMatching(t *testing.T) {
+	m := createTestMatching()
+
+	// Test getters
+	if m.GetID() != "m1" {
+		t.Errorf("Expected ID 'm1', got '%s'", m.GetID())
+	}
+	if m.GetPrompt() != "Match each country to its capital" {
+		t.Errorf("Expected prompt 'Match each country to its capital', got '%s'", m.GetPrompt())
+	}
+	if m.GetDifficulty() != 3 {
+		t.Errorf("Expected difficulty 3, got %d", m.GetDifficulty())
+	}
+	if m.GetTimeLimit() != 60*time.Second {
+		t.Errorf("Expected time limit 60s, got %v", m.GetTimeLimit())
+	}
+
+	// Test CheckAnswer
+	if !m.CheckAnswer("0:2,1:0,2:1") {
+		t.Error("Expected correct pairings to return true")
+	}
+	if !m.CheckAnswer("2:1, 0:2, 1:0") {
+		t.Error("Expected order of pairings to be ignored")
+	}
+	if m.CheckAnswer("0:2,1:0") {
+		t.Error("Expected incomplete pairings to return false")
+	}
+	if m.CheckAnswer("0:2,1:0,2:3") {
+		t.Error("Expected a wrong pairing to return false")
+	}
+	if m.CheckAnswer("0:2,0:0,1:0,2:1") {
+		t.Error("Expected pairing an item twice to return false")
+	}
+	if m.CheckAnswer("0:9") {
+		t.Error("Expected out of range pairing to return false")
+	}
+}
+
+func TestMatchingPartialScore(t *testing.T) {
+	m := createTestMatching()
+
+	// Test all-or-nothing scoring is the default
+	if score := m.PartialScore("0:2,1:0"); score != 0 {
+		t.Errorf("Expected score 0 in all-or-nothing mode, got %v", score)
+	}
+	if score := m.PartialScore("0:2,1:0,2:1"); score != 1 {
+		t.Errorf("Expected score 1 for correct answer, got %v", score)
+	}
+
+	// Test credit per correct pair
+	m.Grading = PARTIAL_CREDIT
+	if score := m.PartialScore("0:2,1:0,2:3"); score != 2.0/3.0 {
+		t.Errorf("Expected score 2/3, got %v", score)
+	}
+	if score := m.PartialScore("0:0,1:1"); score != 0 {
+		t.Errorf("Expected score 0, got %v", score)
+	}
+	if score := m.PartialScore("0:2,0:0"); score != 0 {
+		t.Errorf("Expected score 0 for invalid answer, got %v", score)
+	}
+}
+
+func TestParsePairings(t *testing.T) {
+	pairings, err := ParsePairings("0:1, 2:0")
+	if err != nil {
+		t.Fatalf("Failed to parse pairings: %v", err)
+	}
+	if len(pairings) != 2 || pairings[0] != (Pairing{0, 1}) || pairings[1] != (Pairing{2, 0}) {
+		t.Errorf("Expected [{0 1} {2 0}], got %v", pairings)
+	}
+
+	if _, err := ParsePairings("0-1"); err == nil {
+		t.Error("Expected error for missing separator")
+	}
+	if _, err := ParsePairings("a:1"); err == nil {
+		t.Error("Expected error for invalid index")
+	}
+
+	if EncodePairings(pairings) != "0:1,2:0" {
+		t.Errorf("Expected '0:1,2:0', got '%s'", EncodePairings(pairings))
+	}
+}
@@ -0,0 +1,140 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// MultiResponse asks the user to select every correct option. At least one
+// option must be correct, otherwise no answer is. Answers are encoded with
+// EncodeIndices, e.g. "0,2" or "" for an empty selection. Options listed in
+// PinnedOptions keep their position when options are shuffled.
+type MultiResponse struct {
+	Id                   string        `json:"id"`
+	Prompt               string        `json:"prompt"`
+	Options              []string      `json:"options"`
+	PinnedOptions        []int         `json:"pinnedOptions"`
+	Difficulty           int           `json:"difficulty"`
+	CorrectAnswerIndices []int         `json:"correctAnswerIndices"`
+	Grading              GradingMode   `json:"grading"`
+	Hint                 string        `json:"hint"`
+	Hints                []string      `json:"hints"`
+	TimeLimit            time.Duration `json:"timeLimit"`
+}
+
+func (mr *MultiResponse) GetPrompt() string {
+	return mr.Prompt
+}
+
+func (mr *MultiResponse) GetID() string {
+	return mr.Id
+}
+
+func (mr *MultiResponse) CheckAnswer(answer string) bool {
+	if _, _, ok := mr.selection(answer); !ok {
+		return false
+	}
+	return mr.matchingOptions(answer) == len(mr.Options)
+}
+
+// PartialScore returns the correct options selected minus the wrong options
+// selected, as a fraction of the correct options and never below zero, so an
+// empty selection earns nothing. In ALL_OR_NOTHING mode only a fully correct
+// answer scores.
+func (mr *MultiResponse) PartialScore(answer string) float64 {
+	if mr.Grading != PARTIAL_CREDIT {
+		if mr.CheckAnswer(answer) {
+			return 1
+		}
+		return 0
+	}
+
+	selected, correct, ok := mr.selection(answer)
+	if !ok {
+		return 0
+	}
+	right, wrong := 0, 0
+	for i := range selected {
+		if correct[i] {
+			right++
+		} else {
+			wrong++
+		}
+	}
+	return math.Max(float64(right-wrong)/float64(len(correct)), 0)
+}
+
+// Evaluate grades the answer and reports how many options were handled
+// correctly when it is not fully correct.
+func (mr *MultiResponse) Evaluate(answer Answer) GradeResult {
+	result := checkResult(mr, answer)
+	if !result.IsCorrect {
+		result.Feedback = fmt.Sprintf("%d of %d options were correctly selected or left unselected",
+			mr.matchingOptions(answer.Encode()), len(mr.Options))
+	}
+	return result
+}
+
+func (mr *MultiResponse) GetDifficulty() int {
+	return mr.Difficulty
+}
+
+func (mr *MultiResponse) GetTimeLimit() time.Duration {
+	return mr.TimeLimit
+}
+
+func (mr *MultiResponse) GetHints() []string {
+	return hintList(mr.Hint, mr.Hints)
+}
+
+func (mr *MultiResponse) GetOptions() []string {
+	return mr.Options
+}
+
+func (mr *MultiResponse) ShuffleOptions(r *rand.Rand) []int {
+	return shuffleOptions(r, len(mr.Options), mr.PinnedOptions)
+}
+
+// CanonicalAnswer maps the selected positions in the presented order to the
+// canonical option indices.
+func (mr *MultiResponse) CanonicalAnswer(answer Answer, order []int) Answer {
+	return canonicalIndices(answer.Encode(), order)
+}
+
+// matchingOptions counts the options that were selected if and only if they
+// are correct. An unparsable or out of range answer matches nothing.
+func (mr *MultiResponse) matchingOptions(answer string) int {
+	selected, correct, ok := mr.selection(answer)
+	if !ok {
+		return 0
+	}
+
+	matching := 0
+	for i := range mr.Options {
+		if selected[i] == correct[i] {
+			matching++
+		}
+	}
+	return matching
+}
+
+// selection returns the sets of selected and correct option indices, or
+// false if the answer or the answer key is unparsable or out of range, or if
+// no option is correct.
+func (mr *MultiResponse) selection(answer string) (map[int]bool, map[int]bool, bool) {
+	indices, err := ParseIndices(answer)
+	if err != nil {
+		return nil, nil, false
+	}
+	selected, ok := indexSet(indices, len(mr.Options))
+	if !ok {
+		return nil, nil, false
+	}
+	correct, ok := indexSet(mr.CorrectAnswerIndices, len(mr.Options))
+	if !ok || len(correct) == 0 {
+		return nil, nil, false
+	}
+	return selected, correct, true
+}
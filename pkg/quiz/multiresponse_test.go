@@ -0,0 +1,128 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMultiResponse(t *testing.T) {
+	mr := &MultiResponse{
+		Id:                   "mr1",
+		Prompt:               "Which of these are prime?",
+		Options:              []string{"2", "4", "5", "9"},
+		Difficulty:           2,
+		CorrectAnswerIndices: []int{0, 2},
+		Hint:                 "There are two",
+		TimeLimit:            30 * time.Second,
+	}
+
+	// Test getters
+	if mr.GetID() != "mr1" {
+		t.Errorf("Expected ID 'mr1', got '%s'", mr.GetID())
+	}
+	if mr.GetPrompt() != "Which of these are prime?" {
+		t.Errorf("Expected prompt 'Which of these are prime?', got '%s'", mr.GetPrompt())
+	}
+	if mr.GetDifficulty() != 2 {
+		t.Errorf("Expected difficulty 2, got %d", mr.GetDifficulty())
+	}
+	if mr.GetTimeLimit() != 30*time.Second {
+		t.Errorf("Expected time limit 30s, got %v", mr.GetTimeLimit())
+	}
+
+	// Test CheckAnswer
+	if !mr.CheckAnswer("0,2") {
+		t.Error("Expected correct answer '0,2' to return true")
+	}
+	if !mr.CheckAnswer(" 2, 0 ") {
+		t.Error("Expected order and spacing of indices to be ignored")
+	}
+	if mr.CheckAnswer("0") {
+		t.Error("Expected incomplete answer '0' to return false")
+	}
+	if mr.CheckAnswer("0,1,2") {
+		t.Error("Expected answer with an extra option to return false")
+	}
+	if mr.CheckAnswer("0,7") {
+		t.Error("Expected out of range index to return false")
+	}
+	if mr.CheckAnswer("a,b") {
+		t.Error("Expected unparsable answer to return false")
+	}
+
+	// Test all-or-nothing scoring is the default
+	if score := mr.PartialScore("0"); score != 0 {
+		t.Errorf("Expected score 0 for partial answer in all-or-nothing mode, got %v", score)
+	}
+	if score := mr.PartialScore("0,2"); score != 1 {
+		t.Errorf("Expected score 1 for correct answer, got %v", score)
+	}
+
+	// Test partial credit per option
+	mr.Grading = PARTIAL_CREDIT
+	if score := mr.PartialScore("0"); score != 0.5 {
+		t.Errorf("Expected score 0.5, got %v", score)
+	}
+	if score := mr.PartialScore("0,1,2"); score != 0.5 {
+		t.Errorf("Expected score 0.5 for an extra wrong option, got %v", score)
+	}
+	if score := mr.PartialScore(""); score != 0 {
+		t.Errorf("Expected score 0 for an empty selection, got %v", score)
+	}
+	if score := mr.PartialScore("0,1,2,3"); score != 0 {
+		t.Errorf("Expected score 0 for selecting every option, got %v", score)
+	}
+	if score := mr.PartialScore("1,3"); score != 0 {
+		t.Errorf("Expected score 0, got %v", score)
+	}
+	if score := mr.PartialScore("0,7"); score != 0 {
+		t.Errorf("Expected score 0 for invalid answer, got %v", score)
+	}
+}
+
+func TestMultiResponseNoCorrectOptions(t *testing.T) {
+	mr := &MultiResponse{
+		Id:                   "mr2",
+		Prompt:               "Which of these are even?",
+		Options:              []string{"1", "3", "5"},
+		CorrectAnswerIndices: []int{},
+		Grading:              PARTIAL_CREDIT,
+	}
+
+	// Without a correct option no answer is correct
+	if mr.CheckAnswer("") {
+		t.Error("Expected empty selection to be incorrect")
+	}
+	if mr.CheckAnswer("1") {
+		t.Error("Expected any selection to be incorrect")
+	}
+	if score := mr.PartialScore(""); score != 0 {
+		t.Errorf("Expected score 0, got %v", score)
+	}
+
+	empty := &MultiResponse{Id: "mr3", Prompt: "Select nothing"}
+	if empty.CheckAnswer("") {
+		t.Error("Expected a question without options to reject every answer")
+	}
+	if score := empty.PartialScore(""); score != 0 {
+		t.Errorf("Expected score 0, got %v", score)
+	}
+}
+
+func TestParseIndices(t *testing.T) {
+	indices, err := ParseIndices("3, 1,0")
+	if err != nil {
+		t.Fatalf("Failed to parse indices: %v", err)
+	}
+	if len(indices) != 3 || indices[0] != 3 || indices[1] != 1 || indices[2] != 0 {
+		t.Errorf("Expected [3 1 0], got %v", indices)
+	}
+
+	if _, err := ParseIndices("1,x"); err == nil {
+		t.Error("Expected error for invalid index")
+	}
+
+	if EncodeIndices([]int{0, 2}) != "0,2" {
+		t.Errorf("Expected '0,2', got '%s'", EncodeIndices([]int{0, 2}))
+	}
+}
@@ -0,0 +1,150 @@
+package quiz
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrLinearNavigation is returned when moving back, skipping or jumping to a
+// question in an attempt whose definition requires linear navigation.
+var ErrLinearNavigation = errors.New("quiz only allows moving forward")
+
+// ErrNoSuchQuestion is returned when navigating to, flagging or grading a
+// question index outside the attempt.
+var ErrNoSuchQuestion = errors.New("quiz has no question at that index")
+
+// canNavigate reports whether the definition lets users move freely.
+func (q *Quiz) canNavigate() bool {
+	return q.settings.Navigation == FREE_NAVIGATION
+}
+
+// PreviousQuestion moves back to the previous question. It returns false if
+// the attempt is at its first question, completed, or only moves forward.
+func (q *Quiz) PreviousQuestion() bool {
+	if q.currentIndex == 0 {
+		return false
+	}
+	return q.GoTo(q.currentIndex-1) == nil
+}
+
+// GoTo moves to the question at index. Time on a question adds up over every
+// time it is shown, so leaving it does not reset its time limit.
+func (q *Quiz) GoTo(index int) error {
+	now := q.clock.Now()
+	if q.expire(now) {
+		return ErrQuizExpired
+	}
+	if q.completed {
+		return ErrQuizCompleted
+	}
+	if err := q.checkTransition(AWAITING_ANSWER); err != nil {
+		return err
+	}
+	if !q.canNavigate() {
+		return ErrLinearNavigation
+	}
+	if index < 0 || index >= len(q.questions) {
+		return ErrNoSuchQuestion
+	}
+
+	q.present(index, now)
+	return nil
+}
+
+// Skip leaves the current question to be answered later and moves to the
+// next unanswered question, wrapping around to the start of the attempt.
+// It stays on the current question if no other question is unanswered.
+func (q *Quiz) Skip() error {
+	unanswered := q.UnansweredQuestions()
+	next := q.currentIndex
+	for _, index := range unanswered {
+		if index > q.currentIndex {
+			next = index
+			break
+		}
+	}
+	if next == q.currentIndex && len(unanswered) > 0 && unanswered[0] != q.currentIndex {
+		next = unanswered[0]
+	}
+	return q.GoTo(next)
+}
+
+// checkFlag returns an error if the question at index cannot be flagged or
+// unflagged. Flags can only change while the attempt is being answered.
+func (q *Quiz) checkFlag(index int) error {
+	if q.expire(q.clock.Now()) {
+		return ErrQuizExpired
+	}
+	if q.completed {
+		return ErrQuizCompleted
+	}
+	if err := q.checkTransition(AWAITING_ANSWER); err != nil {
+		return err
+	}
+	if index < 0 || index >= len(q.questions) {
+		return ErrNoSuchQuestion
+	}
+	return nil
+}
+
+// Flag marks the question at index for review.
+func (q *Quiz) Flag(index int) error {
+	if err := q.checkFlag(index); err != nil {
+		return err
+	}
+	q.flagged[index] = true
+	return nil
+}
+
+// Unflag removes the review mark from the question at index.
+func (q *Quiz) Unflag(index int) error {
+	if err := q.checkFlag(index); err != nil {
+		return err
+	}
+	delete(q.flagged, index)
+	return nil
+}
+
+// IsFlagged reports whether the question at index is flagged for review.
+func (q *Quiz) IsFlagged(index int) bool {
+	return q.flagged[index]
+}
+
+// FlaggedQuestions returns the indices of the questions flagged for review
+// in ascending order.
+func (q *Quiz) FlaggedQuestions() []int {
+	flagged := make([]int, 0, len(q.flagged))
+	for index := range q.flagged {
+		flagged = append(flagged, index)
+	}
+	sort.Ints(flagged)
+	return flagged
+}
+
+// UnansweredQuestions returns the indices of the questions that have no
+// answer yet in ascending order.
+func (q *Quiz) UnansweredQuestions() []int {
+	var unanswered []int
+	for i := range q.questions {
+		if q.latestResult(i) == nil {
+			unanswered = append(unanswered, i)
+		}
+	}
+	return unanswered
+}
+
+// SubmitAll finishes the attempt. Questions left unanswered earn no points.
+func (q *Quiz) SubmitAll() error {
+	now := q.clock.Now()
+	if q.expire(now) {
+		return ErrQuizExpired
+	}
+	if q.completed {
+		return ErrQuizCompleted
+	}
+	if err := q.checkTransition(FINISHED); err != nil {
+		return err
+	}
+	q.finish(now)
+	return nil
+}
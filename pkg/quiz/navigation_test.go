@@ -0,0 +1,202 @@
+package quiz
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newNavigableAttempt(t *testing.T, mode NavigationMode) *Quiz {
+	t.Helper()
+	d := NewQuizDefinition("def1", "Navigable", []string{"mc1", "tf1", "fi1"})
+	d.Navigation = mode
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	quiz, err := NewAttempt("a1", d, createTestQuestions())
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	return quiz
+}
+
+func TestLinearNavigation(t *testing.T) {
+	quiz := newNavigableAttempt(t, "")
+	quiz.NextQuestion()
+
+	if quiz.PreviousQuestion() {
+		t.Errorf("Expected a linear attempt not to move back")
+	}
+	if err := quiz.GoTo(0); err != ErrLinearNavigation {
+		t.Errorf("Expected ErrLinearNavigation, got %v", err)
+	}
+	if err := quiz.Skip(); err != ErrLinearNavigation {
+		t.Errorf("Expected ErrLinearNavigation, got %v", err)
+	}
+	if quiz.GetCurrentIndex() != 1 {
+		t.Errorf("Expected current index 1, got %d", quiz.GetCurrentIndex())
+	}
+
+	// The last NextQuestion still finishes the attempt
+	quiz.NextQuestion()
+	quiz.NextQuestion()
+	if !quiz.IsCompleted() {
+		t.Errorf("Expected the attempt to be completed")
+	}
+}
+
+func TestFreeNavigation(t *testing.T) {
+	quiz := newNavigableAttempt(t, FREE_NAVIGATION)
+
+	if err := quiz.Skip(); err != nil {
+		t.Fatalf("Failed to skip: %v", err)
+	}
+	if quiz.GetCurrentIndex() != 1 {
+		t.Errorf("Expected current index 1, got %d", quiz.GetCurrentIndex())
+	}
+	quiz.SubmitAnswer("true")
+
+	if err := quiz.GoTo(2); err != nil {
+		t.Fatalf("Failed to go to question 2: %v", err)
+	}
+	if quiz.NextQuestion() {
+		t.Errorf("Expected no question after the last")
+	}
+	if quiz.IsCompleted() {
+		t.Errorf("Expected the attempt to wait for SubmitAll")
+	}
+
+	// Skipping the last question wraps around to the first unanswered one
+	if err := quiz.Skip(); err != nil {
+		t.Fatalf("Failed to skip: %v", err)
+	}
+	if quiz.GetCurrentIndex() != 0 {
+		t.Errorf("Expected current index 0, got %d", quiz.GetCurrentIndex())
+	}
+	quiz.SubmitAnswer("4")
+
+	if quiz.PreviousQuestion() {
+		t.Errorf("Expected PreviousQuestion to fail at the first question")
+	}
+	if err := quiz.GoTo(3); err != ErrNoSuchQuestion {
+		t.Errorf("Expected ErrNoSuchQuestion, got %v", err)
+	}
+
+	if got := quiz.UnansweredQuestions(); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Expected unanswered [2], got %v", got)
+	}
+
+	if err := quiz.SubmitAll(); err != nil {
+		t.Fatalf("Failed to submit all: %v", err)
+	}
+	if !quiz.IsCompleted() || quiz.GetStatus() != FINISHED {
+		t.Errorf("Expected a finished attempt, got %s", quiz.GetStatus())
+	}
+	if quiz.GetScore() != 3 {
+		t.Errorf("Expected score 3, got %v", quiz.GetScore())
+	}
+	if err := quiz.GoTo(0); err != ErrQuizCompleted {
+		t.Errorf("Expected ErrQuizCompleted, got %v", err)
+	}
+	if err := quiz.SubmitAll(); err != ErrQuizCompleted {
+		t.Errorf("Expected ErrQuizCompleted, got %v", err)
+	}
+}
+
+func TestFlagQuestions(t *testing.T) {
+	quiz := newNavigableAttempt(t, FREE_NAVIGATION)
+
+	if err := quiz.Flag(2); err != nil {
+		t.Fatalf("Failed to flag: %v", err)
+	}
+	if err := quiz.Flag(0); err != nil {
+		t.Fatalf("Failed to flag: %v", err)
+	}
+	if err := quiz.Flag(5); err != ErrNoSuchQuestion {
+		t.Errorf("Expected ErrNoSuchQuestion, got %v", err)
+	}
+	if got := quiz.FlaggedQuestions(); !reflect.DeepEqual(got, []int{0, 2}) {
+		t.Errorf("Expected flagged [0 2], got %v", got)
+	}
+
+	if err := quiz.Unflag(0); err != nil {
+		t.Fatalf("Failed to unflag: %v", err)
+	}
+	if quiz.IsFlagged(0) || !quiz.IsFlagged(2) {
+		t.Errorf("Expected only question 2 to be flagged, got %v", quiz.FlaggedQuestions())
+	}
+
+	restored := RestoreQuiz(quiz.State(), Options{})
+	if got := restored.FlaggedQuestions(); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("Expected restored flags [2], got %v", got)
+	}
+}
+
+func TestNavigationKeepsQuestionTime(t *testing.T) {
+	quiz, clock := newTimedAttempt(t, func(d *QuizDefinition) {
+		d.Navigation = FREE_NAVIGATION
+		d.LatePolicy = REJECT_LATE
+	})
+
+	// mc1 allows 30 seconds, of which 20 are spent before leaving it
+	clock.Advance(20 * time.Second)
+	if err := quiz.GoTo(1); err != nil {
+		t.Fatalf("Failed to go to question 1: %v", err)
+	}
+	clock.Advance(time.Minute)
+	if !quiz.PreviousQuestion() {
+		t.Fatalf("Expected to move back to question 0")
+	}
+	if remaining, ok := quiz.QuestionRemainingTime(); !ok || remaining != 10*time.Second {
+		t.Errorf("Expected 10s left, got %v", remaining)
+	}
+	clock.Advance(11 * time.Second)
+	if _, err := quiz.Submit(TextAnswer("4")); err != ErrQuestionExpired {
+		t.Errorf("Expected ErrQuestionExpired, got %v", err)
+	}
+
+	// tf1 allows 15 seconds and was left after a minute
+	if err := quiz.Skip(); err != nil {
+		t.Fatalf("Failed to skip: %v", err)
+	}
+	if quiz.GetCurrentIndex() != 1 {
+		t.Errorf("Expected current index 1, got %d", quiz.GetCurrentIndex())
+	}
+	if _, err := quiz.Submit(TextAnswer("true")); err != ErrQuestionExpired {
+		t.Errorf("Expected ErrQuestionExpired, got %v", err)
+	}
+
+	restored := RestoreQuiz(quiz.State(), Options{Clock: clock})
+	if got := restored.GetTimeSpent(0); got != 31*time.Second {
+		t.Errorf("Expected 31s spent on question 0, got %v", got)
+	}
+	if remaining, ok := restored.QuestionRemainingTime(); !ok || remaining != 0 {
+		t.Errorf("Expected no time left, got %v", remaining)
+	}
+}
+
+func TestFlagAfterAttemptEnds(t *testing.T) {
+	quiz := newNavigableAttempt(t, FREE_NAVIGATION)
+	if err := quiz.Quit(); err != nil {
+		t.Fatalf("Failed to quit: %v", err)
+	}
+	var transition *TransitionError
+	if err := quiz.Flag(0); !errors.As(err, &transition) {
+		t.Errorf("Expected a TransitionError, got %v", err)
+	}
+	if err := quiz.Unflag(0); !errors.As(err, &transition) {
+		t.Errorf("Expected a TransitionError, got %v", err)
+	}
+
+	quiz = newNavigableAttempt(t, FREE_NAVIGATION)
+	if err := quiz.SubmitAll(); err != nil {
+		t.Fatalf("Failed to submit: %v", err)
+	}
+	if err := quiz.Flag(0); err != ErrQuizCompleted {
+		t.Errorf("Expected ErrQuizCompleted, got %v", err)
+	}
+	if len(quiz.FlaggedQuestions()) != 0 {
+		t.Errorf("Expected no flagged questions, got %v", quiz.FlaggedQuestions())
+	}
+}
@@ -0,0 +1,113 @@
+package quiz
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TextMatching configures how free text answers are compared with the
+// accepted answers. Surrounding whitespace is always ignored and runs of
+// whitespace are treated as a single space. The zero value compares the
+// remaining text exactly.
+type TextMatching struct {
+	IgnoreCase        bool `json:"ignoreCase"`
+	IgnorePunctuation bool `json:"ignorePunctuation"`
+	IgnoreDiacritics  bool `json:"ignoreDiacritics"`
+	// MaxEditDistance accepts answers within this many single character
+	// insertions, deletions or substitutions of an accepted answer.
+	MaxEditDistance int `json:"maxEditDistance"`
+}
+
+// Normalize applies the matching rules to s so that two strings match
+// exactly when their normalized forms are equal.
+func (m TextMatching) Normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.TrimSpace(s) {
+		if m.IgnorePunctuation && (unicode.IsPunct(r) || unicode.IsSymbol(r)) {
+			continue
+		}
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space && b.Len() > 0 {
+			b.WriteRune(' ')
+		}
+		space = false
+		if m.IgnoreDiacritics {
+			r = removeDiacritic(r)
+		}
+		if m.IgnoreCase {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Matches reports whether answer matches expected under the matching rules.
+func (m TextMatching) Matches(answer, expected string) bool {
+	a, e := m.Normalize(answer), m.Normalize(expected)
+	if a == e {
+		return true
+	}
+	return m.MaxEditDistance > 0 && editDistance(a, e) <= m.MaxEditDistance
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// diacritics maps accented Latin letters to their unaccented base letter.
+var diacritics = map[rune]rune{}
+
+func init() {
+	groups := map[rune]string{
+		'a': "àáâãäåāăą", 'A': "ÀÁÂÃÄÅĀĂĄ",
+		'c': "çćĉċč", 'C': "ÇĆĈĊČ",
+		'd': "ď", 'D': "Ď",
+		'e': "èéêëēĕėęě", 'E': "ÈÉÊËĒĔĖĘĚ",
+		'g': "ĝğġģ", 'G': "ĜĞĠĢ",
+		'i': "ìíîïĩīĭį", 'I': "ÌÍÎÏĨĪĬĮ",
+		'l': "ĺļľł", 'L': "ĹĻĽŁ",
+		'n': "ñńņň", 'N': "ÑŃŅŇ",
+		'o': "òóôõöøōŏő", 'O': "ÒÓÔÕÖØŌŎŐ",
+		'r': "ŕŗř", 'R': "ŔŖŘ",
+		's': "śŝşš", 'S': "ŚŜŞŠ",
+		't': "ţť", 'T': "ŢŤ",
+		'u': "ùúûüũūŭůűų", 'U': "ÙÚÛÜŨŪŬŮŰŲ",
+		'y': "ýÿ", 'Y': "ÝŸ",
+		'z': "źżž", 'Z': "ŹŻŽ",
+	}
+	for base, accented := range groups {
+		for _, r := range accented {
+			diacritics[r] = base
+		}
+	}
+}
+
+func removeDiacritic(r rune) rune {
+	if base, ok := diacritics[r]; ok {
+		return base
+	}
+	return r
+}
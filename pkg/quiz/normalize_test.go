@@ -0,0 +1,53 @@
+package quiz
+
+import "testing"
+
+func TestTextMatchingNormalize(t *testing.T) {
+	tests := []struct {
+		matching TextMatching
+		input    string
+		expected string
+	}{
+		{TextMatching{IgnoreCase: true}, "  Hello   World ", "hello world"},
+		{TextMatching{}, "Hello\tWorld", "Hello World"},
+		{TextMatching{IgnorePunctuation: true, IgnoreCase: true}, "It's a dog-eat-dog world!", "its a dogeatdog world"},
+		{TextMatching{IgnoreDiacritics: true, IgnoreCase: true}, "Crème Brûlée", "creme brulee"},
+		{TextMatching{IgnoreDiacritics: true}, "Ångström", "Angstrom"},
+		{TextMatching{IgnoreCase: true}, "Ångström", "ångström"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.matching.Normalize(tt.input); got != tt.expected {
+			t.Errorf("Expected '%s' for '%s' with %+v, got '%s'", tt.expected, tt.input, tt.matching, got)
+		}
+	}
+}
+
+func TestTextMatchingMatches(t *testing.T) {
+	fuzzy := TextMatching{MaxEditDistance: 2}
+	if !fuzzy.Matches("Mississipi", "Mississippi") {
+		t.Error("Expected one missing letter to match")
+	}
+	if fuzzy.Matches("Misisipi", "Mississippi") {
+		t.Error("Expected three missing letters not to match")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"café", "cafe", 1},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.distance {
+			t.Errorf("Expected distance %d between '%s' and '%s', got %d", tt.distance, tt.a, tt.b, got)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Numeric asks for a number. The answer is correct when it lies within
+// Tolerance (absolute) or RelativeTolerance (a fraction of CorrectAnswer) of
+// CorrectAnswer, or, if AllowRange is set, between Min and Max inclusive.
+//
+// If Unit is set the user may give the value in any unit of the same
+// dimension, e.g. "1.5 km" for a question expecting metres. A value without
+// a unit is taken to be in Unit. Unit symbols are case-sensitive, as SI
+// prefixes are: "mV" is a millivolt and "MV" a megavolt, so "KM" or "mhz"
+// are not recognised.
+type Numeric struct {
+	Id                string        `json:"id"`
+	Prompt            string        `json:"prompt"`
+	Difficulty        int           `json:"difficulty"`
+	CorrectAnswer     float64       `json:"correctAnswer"`
+	Tolerance         float64       `json:"tolerance"`
+	RelativeTolerance float64       `json:"relativeTolerance"`
+	AllowRange        bool          `json:"allowRange"`
+	Min               float64       `json:"min"`
+	Max               float64       `json:"max"`
+	Unit              string        `json:"unit"`
+	Hint              string        `json:"hint"`
+	Hints             []string      `json:"hints"`
+	TimeLimit         time.Duration `json:"timeLimit"`
+}
+
+func (n *Numeric) GetPrompt() string {
+	return n.Prompt
+}
+
+func (n *Numeric) GetID() string {
+	return n.Id
+}
+
+func (n *Numeric) CheckAnswer(answer string) bool {
+	value, unit, err := ParseQuantity(answer)
+	if err != nil {
+		return false
+	}
+	value, ok := convertUnit(value, unit, n.Unit)
+	if !ok {
+		return false
+	}
+
+	if n.AllowRange {
+		return value >= n.Min && value <= n.Max
+	}
+
+	tolerance := math.Max(n.Tolerance, n.RelativeTolerance*math.Abs(n.CorrectAnswer))
+	// Allow for rounding introduced by parsing and unit conversion
+	tolerance += 1e-9 * math.Max(1, math.Abs(n.CorrectAnswer))
+	return math.Abs(value-n.CorrectAnswer) <= tolerance
+}
+
+// Validate checks that an allowed range does not end before it starts.
+func (n *Numeric) Validate() error {
+	if n.AllowRange && n.Min > n.Max {
+		return fmt.Errorf("range minimum %v is above its maximum %v", n.Min, n.Max)
+	}
+	return nil
+}
+
+func (n *Numeric) GetDifficulty() int {
+	return n.Difficulty
+}
+
+func (n *Numeric) GetTimeLimit() time.Duration {
+	return n.TimeLimit
+}
+
+func (n *Numeric) GetHints() []string {
+	return hintList(n.Hint, n.Hints)
+}
+
+// ErrAmbiguousNumber is returned by ParseQuantity for numbers such as
+// "1,500" that may mean either 1.5 or 1500.
+var ErrAmbiguousNumber = errors.New("number is ambiguous, a single comma before three digits may group thousands or separate decimals")
+
+// ParseQuantity splits an answer such as "3,14", "-2.5e3" or "1.5 km" into
+// its numeric value and unit. A single comma is read as a decimal separator,
+// unless exactly three digits follow it: such numbers are rejected with
+// ErrAmbiguousNumber. When both commas and dots appear, the last one is the
+// decimal separator and the other groups thousands. Numbers whose grouping
+// separators do not split off groups of three digits, such as "1.2.3", are
+// rejected.
+func ParseQuantity(answer string) (float64, string, error) {
+	answer = strings.TrimSpace(answer)
+	end := 0
+	for end < len(answer) {
+		c := rune(answer[end])
+		if unicode.IsDigit(c) || strings.ContainsRune("+-.,", c) {
+			end++
+			continue
+		}
+		// An exponent must be followed by a digit or sign, otherwise it is a unit
+		if (c == 'e' || c == 'E') && end+1 < len(answer) &&
+			(unicode.IsDigit(rune(answer[end+1])) || answer[end+1] == '+' || answer[end+1] == '-') {
+			end += 2
+			continue
+		}
+		break
+	}
+
+	if isAmbiguousComma(answer[:end]) {
+		return 0, "", ErrAmbiguousNumber
+	}
+	number, ok := normalizeDecimal(answer[:end])
+	if !ok {
+		return 0, "", fmt.Errorf("invalid number %q", answer)
+	}
+	unit := strings.TrimSpace(answer[end:])
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid number %q", answer)
+	}
+	return value, unit, nil
+}
+
+// normalizeDecimal rewrites locale specific separators so the result can be
+// parsed by strconv.ParseFloat. A separator that appears more than once can
+// only be grouping thousands. It returns false if the decimal separator
+// appears more than once or the grouping separator does not split the
+// integer part into groups of three digits.
+func normalizeDecimal(number string) (string, bool) {
+	commas := strings.Count(number, ",")
+	dots := strings.Count(number, ".")
+	var decimal, grouping string
+	switch {
+	case commas == 0 && dots == 0:
+		return number, true
+	case commas == 0 && dots == 1:
+		decimal = "."
+	case commas == 0:
+		grouping = "."
+	case dots == 0 && commas == 1:
+		decimal = ","
+	case dots == 0:
+		grouping = ","
+	case strings.LastIndex(number, ",") > strings.LastIndex(number, "."):
+		decimal, grouping = ",", "."
+	default:
+		decimal, grouping = ".", ","
+	}
+
+	integer, fraction := number, ""
+	if decimal != "" {
+		if strings.Count(number, decimal) > 1 {
+			return "", false
+		}
+		i := strings.Index(number, decimal)
+		integer, fraction = number[:i], "."+number[i+1:]
+	}
+	if grouping != "" {
+		if !isGrouped(integer, grouping) {
+			return "", false
+		}
+		integer = strings.ReplaceAll(integer, grouping, "")
+	}
+	return integer + fraction, true
+}
+
+// isGrouped reports whether separator splits integer, after its sign, into
+// a leading group of one to three digits followed by groups of exactly three.
+func isGrouped(integer, separator string) bool {
+	groups := strings.Split(strings.TrimLeft(integer, "+-"), separator)
+	for i, group := range groups {
+		if group == "" || len(group) > 3 || (i > 0 && len(group) != 3) {
+			return false
+		}
+		for _, c := range group {
+			if !unicode.IsDigit(c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isAmbiguousComma reports whether number has a single comma, no dot and
+// exactly three digits after the comma.
+func isAmbiguousComma(number string) bool {
+	if strings.Count(number, ",") != 1 || strings.Contains(number, ".") {
+		return false
+	}
+	fraction := number[strings.Index(number, ",")+1:]
+	if len(fraction) < 3 {
+		return false
+	}
+	for _, c := range fraction[:3] {
+		if !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return len(fraction) == 3 || !unicode.IsDigit(rune(fraction[3]))
+}
+
+type unitDef struct {
+	dimension string
+	factor    float64
+}
+
+// units maps unit symbols to their dimension and size in the SI base unit.
+// Symbols are looked up exactly, since case tells prefixes apart.
+var units = map[string]unitDef{
+	"mm": {"length", 0.001},
+	"cm": {"length", 0.01},
+	"m":  {"length", 1},
+	"km": {"length", 1000},
+	"in": {"length", 0.0254},
+	"ft": {"length", 0.3048},
+	"mi": {"length", 1609.344},
+
+	"mg": {"mass", 0.000001},
+	"g":  {"mass", 0.001},
+	"kg": {"mass", 1},
+	"t":  {"mass", 1000},
+	"lb": {"mass", 0.45359237},
+
+	"ms":  {"time", 0.001},
+	"s":   {"time", 1},
+	"min": {"time", 60},
+	"h":   {"time", 3600},
+
+	"ml": {"volume", 0.000001},
+	"l":  {"volume", 0.001},
+
+	"mA": {"current", 0.001},
+	"A":  {"current", 1},
+
+	"mV": {"voltage", 0.001},
+	"V":  {"voltage", 1},
+	"kV": {"voltage", 1000},
+
+	"W":  {"power", 1},
+	"kW": {"power", 1000},
+
+	"J":  {"energy", 1},
+	"kJ": {"energy", 1000},
+
+	"Pa":  {"pressure", 1},
+	"kPa": {"pressure", 1000},
+
+	"N": {"force", 1},
+
+	"Hz":  {"frequency", 1},
+	"kHz": {"frequency", 1000},
+	"MHz": {"frequency", 1000000},
+}
+
+// convertUnit converts value from one unit to another. A missing unit is
+// taken to be the expected one. Units outside the table only match
+// themselves.
+func convertUnit(value float64, from, to string) (float64, bool) {
+	if from == "" || from == to {
+		return value, true
+	}
+	if to == "" {
+		return 0, false
+	}
+	fromDef, ok := units[from]
+	if !ok {
+		return 0, false
+	}
+	toDef, ok := units[to]
+	if !ok || fromDef.dimension != toDef.dimension {
+		return 0, false
+	}
+	return value * fromDef.factor / toDef.factor, true
+}
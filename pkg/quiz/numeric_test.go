@@ -0,0 +1,159 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNumeric(t *testing.T) {
+	n := &Numeric{
+		Id:            "n1",
+		Prompt:        "What is the value of pi to two decimal places?",
+		Difficulty:    2,
+		CorrectAnswer: 3.14,
+		Tolerance:     0.005,
+		Hint:          "It starts with 3",
+		TimeLimit:     30 * time.Second,
+	}
+
+	// Test getters
+	if n.GetID() != "n1" {
+		t.Errorf("Expected ID 'n1', got '%s'", n.GetID())
+	}
+	if n.GetPrompt() != "What is the value of pi to two decimal places?" {
+		t.Errorf("Expected prompt 'What is the value of pi to two decimal places?', got '%s'", n.GetPrompt())
+	}
+	if n.GetDifficulty() != 2 {
+		t.Errorf("Expected difficulty 2, got %d", n.GetDifficulty())
+	}
+	if n.GetTimeLimit() != 30*time.Second {
+		t.Errorf("Expected time limit 30s, got %v", n.GetTimeLimit())
+	}
+
+	// Test CheckAnswer with absolute tolerance
+	for _, answer := range []string{"3.14", "3,14", " 3.142 ", "3.14e0", "314E-2"} {
+		if !n.CheckAnswer(answer) {
+			t.Errorf("Expected '%s' to be correct", answer)
+		}
+	}
+	for _, answer := range []string{"3.15", "3", "pi", "", "3.14 m"} {
+		if n.CheckAnswer(answer) {
+			t.Errorf("Expected '%s' to be incorrect", answer)
+		}
+	}
+}
+
+func TestNumericExactValue(t *testing.T) {
+	n := &Numeric{Id: "n2", Prompt: "What is 9 / 3?", CorrectAnswer: 3}
+
+	for _, answer := range []string{"3", "3.0", "3,00", "+3"} {
+		if !n.CheckAnswer(answer) {
+			t.Errorf("Expected '%s' to be correct", answer)
+		}
+	}
+	if n.CheckAnswer("3.01") {
+		t.Error("Expected '3.01' to be incorrect without tolerance")
+	}
+}
+
+func TestNumericRelativeTolerance(t *testing.T) {
+	n := &Numeric{Id: "n3", Prompt: "Speed of light in m/s?", CorrectAnswer: 299792458, RelativeTolerance: 0.01}
+
+	if !n.CheckAnswer("3e8") {
+		t.Error("Expected '3e8' to be within 1%")
+	}
+	if !n.CheckAnswer("299.792.458") {
+		t.Error("Expected dot grouped thousands to be accepted")
+	}
+	if n.CheckAnswer("2.9e8") {
+		t.Error("Expected '2.9e8' to be outside 1%")
+	}
+}
+
+func TestNumericRange(t *testing.T) {
+	n := &Numeric{Id: "n4", Prompt: "Name a number between 10 and 20", AllowRange: true, Min: 10, Max: 20}
+
+	if !n.CheckAnswer("10") || !n.CheckAnswer("15,5") || !n.CheckAnswer("20") {
+		t.Error("Expected values inside the range to be correct")
+	}
+	if n.CheckAnswer("9.99") || n.CheckAnswer("1,000.5") {
+		t.Error("Expected values outside the range to be incorrect")
+	}
+
+	if err := n.Validate(); err != nil {
+		t.Errorf("Expected range to be valid, got %v", err)
+	}
+	n.Min, n.Max = 20, 10
+	if err := n.Validate(); err == nil {
+		t.Error("Expected a range ending before it starts to be rejected")
+	}
+}
+
+func TestNumericUnits(t *testing.T) {
+	n := &Numeric{Id: "n5", Prompt: "How far is 1.5 km in metres?", CorrectAnswer: 1500, Unit: "m"}
+
+	for _, answer := range []string{"1500", "1500 m", "1.5 km", "1,5km", "150000 cm"} {
+		if !n.CheckAnswer(answer) {
+			t.Errorf("Expected '%s' to be correct", answer)
+		}
+	}
+	for _, answer := range []string{"1.5 kg", "1500 parsecs", "1.5", "1,500 m", "1.5 KM"} {
+		if n.CheckAnswer(answer) {
+			t.Errorf("Expected '%s' to be incorrect", answer)
+		}
+	}
+
+	// Units that differ only in case are kept apart
+	voltage := &Numeric{Id: "n7", Prompt: "How many volts is 2 mV?", CorrectAnswer: 0.002, Unit: "V"}
+	if !voltage.CheckAnswer("2 mV") || voltage.CheckAnswer("2 MV") {
+		t.Error("Expected '2 mV' to be correct and '2 MV' not to be a known unit")
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		input string
+		value float64
+		unit  string
+	}{
+		{"42", 42, ""},
+		{"-2.5e3", -2500, ""},
+		{"1.234,5", 1234.5, ""},
+		{"1,234.5", 1234.5, ""},
+		{"1,000,000", 1000000, ""},
+		{"9.81 m", 9.81, "m"},
+		{"3e", 3, "e"},
+	}
+
+	for _, tt := range tests {
+		value, unit, err := ParseQuantity(tt.input)
+		if err != nil {
+			t.Errorf("Failed to parse '%s': %v", tt.input, err)
+			continue
+		}
+		if value != tt.value || unit != tt.unit {
+			t.Errorf("Expected %v '%s' for '%s', got %v '%s'", tt.value, tt.unit, tt.input, value, unit)
+		}
+	}
+
+	if _, _, err := ParseQuantity("abc"); err == nil {
+		t.Error("Expected error for non numeric input")
+	}
+	for _, input := range []string{"1,500", "-2,000 m", "3,141e2"} {
+		if _, _, err := ParseQuantity(input); err != ErrAmbiguousNumber {
+			t.Errorf("Expected ErrAmbiguousNumber for '%s', got %v", input, err)
+		}
+	}
+	for _, input := range []string{"1,5", "1,50", "1,5000"} {
+		if _, _, err := ParseQuantity(input); err != nil {
+			t.Errorf("Expected '%s' to parse, got %v", input, err)
+		}
+	}
+
+	// Separators that cannot group thousands are rejected
+	for _, input := range []string{"1.2.3", "1,2,3", "1.234.5", "1,2.5", "1.234,5.6", "1.2,3,4"} {
+		if _, _, err := ParseQuantity(input); err == nil {
+			t.Errorf("Expected '%s' to be rejected", input)
+		}
+	}
+}
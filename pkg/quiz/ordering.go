@@ -0,0 +1,160 @@
+package quiz
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// INVERSION_CREDIT grades an Ordering answer by how many pairs of items are
+// in the wrong relative order rather than by how many items are in place.
+const INVERSION_CREDIT GradingMode = "INVERSION_CREDIT"
+
+// Ordering asks the user to arrange Items into the sequence given by
+// CorrectOrderIndices. Items are stored in their canonical order and, when
+// the definition shuffles answers, shown in the attempt's option order.
+// Answers list item indices in the order chosen by the user, encoded with
+// EncodeIndices.
+type Ordering struct {
+	Id                  string        `json:"id"`
+	Prompt              string        `json:"prompt"`
+	Items               []string      `json:"items"`
+	CorrectOrderIndices []int         `json:"correctOrderIndices"`
+	Difficulty          int           `json:"difficulty"`
+	Grading             GradingMode   `json:"grading"`
+	Hint                string        `json:"hint"`
+	Hints               []string      `json:"hints"`
+	TimeLimit           time.Duration `json:"timeLimit"`
+}
+
+func (o *Ordering) GetPrompt() string {
+	return o.Prompt
+}
+
+func (o *Ordering) GetID() string {
+	return o.Id
+}
+
+func (o *Ordering) CheckAnswer(answer string) bool {
+	order, ok := o.parseOrder(answer)
+	if !ok || len(order) != len(o.CorrectOrderIndices) {
+		return false
+	}
+	for i := range order {
+		if order[i] != o.CorrectOrderIndices[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// PartialScore grades the answer according to the question's GradingMode.
+// PARTIAL_CREDIT awards the fraction of items in their correct position and
+// INVERSION_CREDIT awards one minus the fraction of pairs that are swapped.
+func (o *Ordering) PartialScore(answer string) float64 {
+	order, ok := o.parseOrder(answer)
+	if !ok || len(order) != len(o.CorrectOrderIndices) || len(order) == 0 {
+		return 0
+	}
+
+	switch o.Grading {
+	case PARTIAL_CREDIT:
+		inPlace := 0
+		for i := range order {
+			if order[i] == o.CorrectOrderIndices[i] {
+				inPlace++
+			}
+		}
+		return float64(inPlace) / float64(len(order))
+	case INVERSION_CREDIT:
+		if len(order) == 1 {
+			return 1
+		}
+		rank := make(map[int]int, len(o.CorrectOrderIndices))
+		for pos, idx := range o.CorrectOrderIndices {
+			rank[idx] = pos
+		}
+		inversions := 0
+		for i := 0; i < len(order); i++ {
+			for j := i + 1; j < len(order); j++ {
+				if rank[order[i]] > rank[order[j]] {
+					inversions++
+				}
+			}
+		}
+		maxInversions := len(order) * (len(order) - 1) / 2
+		return 1 - float64(inversions)/float64(maxInversions)
+	default:
+		if o.CheckAnswer(answer) {
+			return 1
+		}
+		return 0
+	}
+}
+
+// Evaluate grades the answer and reports how many items were in the right
+// position when it is not fully correct.
+func (o *Ordering) Evaluate(answer Answer) GradeResult {
+	result := checkResult(o, answer)
+	if !result.IsCorrect {
+		order, ok := o.parseOrder(answer.Encode())
+		if ok {
+			inPlace := 0
+			for i := range order {
+				if i < len(o.CorrectOrderIndices) && order[i] == o.CorrectOrderIndices[i] {
+					inPlace++
+				}
+			}
+			result.Feedback = fmt.Sprintf("%d of %d items are in the correct position", inPlace, len(o.Items))
+		} else {
+			result.Feedback = "Every item must be placed exactly once"
+		}
+	}
+	return result
+}
+
+func (o *Ordering) GetDifficulty() int {
+	return o.Difficulty
+}
+
+func (o *Ordering) GetTimeLimit() time.Duration {
+	return o.TimeLimit
+}
+
+func (o *Ordering) GetHints() []string {
+	return hintList(o.Hint, o.Hints)
+}
+
+func (o *Ordering) GetOptions() []string {
+	return o.Items
+}
+
+// ShuffleOptions returns a random item order. When there is more than one
+// item the result never matches the correct order.
+func (o *Ordering) ShuffleOptions(r *rand.Rand) []int {
+	order := shuffleOptions(r, len(o.Items), nil)
+	if len(order) > 1 && o.CheckAnswer(EncodeIndices(order)) {
+		order = append(order[1:], order[0])
+	}
+	return order
+}
+
+// CanonicalAnswer maps item positions in the presented order back to
+// canonical item indices.
+func (o *Ordering) CanonicalAnswer(answer Answer, order []int) Answer {
+	return canonicalIndices(answer.Encode(), order)
+}
+
+// parseOrder parses an answer and checks that it is a permutation of the
+// item indices.
+func (o *Ordering) parseOrder(answer string) ([]int, bool) {
+	order, err := ParseIndices(answer)
+	if err != nil || len(order) != len(o.Items) {
+		return nil, false
+	}
+	set, ok := indexSet(order, len(o.Items))
+	if !ok || len(set) != len(order) {
+		return nil, false
+	}
+	return order, true
+}
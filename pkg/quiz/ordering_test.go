@@ -0,0 +1,113 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func createTestOrdering() *Ordering {
+	return &Ordering{
+		Id:                  "o1",
+		Prompt:              "Order the incident response steps",
+		Items:               []string{"Contain", "Identify", "Recover", "Eradicate"},
+		CorrectOrderIndices: []int{1, 0, 3, 2},
+		Difficulty:          2,
+		Hint:                "Recovery comes last",
+		TimeLimit:           60 * time.Second,
+	}
+}
+
+func TestOrdering(t *testing.T) {
+	o := createTestOrdering()
+
+	// Test getters
+	if o.GetID() != "o1" {
+		t.Errorf("Expected ID 'o1', got '%s'", o.GetID())
+	}
+	if o.GetPrompt() != "Order the incident response steps" {
+		t.Errorf("Expected prompt 'Order the incident response steps', got '%s'", o.GetPrompt())
+	}
+	if o.GetDifficulty() != 2 {
+		t.Errorf("Expected difficulty 2, got %d", o.GetDifficulty())
+	}
+	if o.GetTimeLimit() != 60*time.Second {
+		t.Errorf("Expected time limit 60s, got %v", o.GetTimeLimit())
+	}
+
+	// Test CheckAnswer
+	if !o.CheckAnswer("1,0,3,2") {
+		t.Error("Expected correct order to return true")
+	}
+	if o.CheckAnswer("0,1,3,2") {
+		t.Error("Expected wrong order to return false")
+	}
+	if o.CheckAnswer("1,0,3") {
+		t.Error("Expected incomplete order to return false")
+	}
+	if o.CheckAnswer("1,1,3,2") {
+		t.Error("Expected repeated item to return false")
+	}
+}
+
+func TestOrderingPartialScore(t *testing.T) {
+	o := createTestOrdering()
+
+	// Test exact match is the default
+	if score := o.PartialScore("0,1,3,2"); score != 0 {
+		t.Errorf("Expected score 0 in all-or-nothing mode, got %v", score)
+	}
+	if score := o.PartialScore("1,0,3,2"); score != 1 {
+		t.Errorf("Expected score 1 for correct order, got %v", score)
+	}
+
+	// Test credit for items in the correct position
+	o.Grading = PARTIAL_CREDIT
+	if score := o.PartialScore("0,1,3,2"); score != 0.5 {
+		t.Errorf("Expected score 0.5, got %v", score)
+	}
+
+	// Test credit based on inversion count
+	o.Grading = INVERSION_CREDIT
+	if score := o.PartialScore("0,1,3,2"); score != 1-1.0/6.0 {
+		t.Errorf("Expected score 5/6 for a single swap, got %v", score)
+	}
+	if score := o.PartialScore("2,3,0,1"); score != 0 {
+		t.Errorf("Expected score 0 for reversed order, got %v", score)
+	}
+	if score := o.PartialScore("2,3,0"); score != 0 {
+		t.Errorf("Expected score 0 for invalid answer, got %v", score)
+	}
+}
+
+func TestOrderingShuffleOptions(t *testing.T) {
+	o := createTestOrdering()
+
+	first := o.ShuffleOptions(NewRand(1))
+	second := o.ShuffleOptions(NewRand(1))
+	if EncodeIndices(first) != EncodeIndices(second) {
+		t.Errorf("Expected the same order for the same seed, got %v and %v", first, second)
+	}
+	if len(first) != len(o.Items) {
+		t.Fatalf("Expected %d items, got %d", len(o.Items), len(first))
+	}
+
+	// The correct order must never be presented, whatever the seed
+	for seed := int64(0); seed < 100; seed++ {
+		if o.CheckAnswer(EncodeIndices(o.ShuffleOptions(NewRand(seed)))) {
+			t.Fatalf("Seed %d presented the correct order", seed)
+		}
+	}
+
+	// Answers given by presented position are mapped back to the items
+	var positions IndicesAnswer
+	for _, item := range o.CorrectOrderIndices {
+		for position, index := range first {
+			if index == item {
+				positions = append(positions, position)
+			}
+		}
+	}
+	if !o.CheckAnswer(o.CanonicalAnswer(positions, first).Encode()) {
+		t.Errorf("Expected presented positions %v to map to the correct order", positions)
+	}
+}
@@ -0,0 +1,93 @@
+package quiz
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// QuestionFilter selects questions by their tags, difficulty and type. A
+// question must have every tag in Tags and one of the Types, which are the
+// type names used by the question store such as "MULTI_CHOICE". Empty Tags
+// or Types and zero difficulty bounds match everything.
+type QuestionFilter struct {
+	Tags          []string `json:"tags"`
+	MinDifficulty int      `json:"minDifficulty"`
+	MaxDifficulty int      `json:"maxDifficulty"`
+	Types         []string `json:"types"`
+}
+
+// QuestionPool describes Draw questions picked at random from the questions
+// matching its filter each time an attempt is started.
+type QuestionPool struct {
+	Id string `json:"id"`
+	QuestionFilter
+	Draw int `json:"draw"`
+}
+
+// Validate checks that the pool can be drawn from.
+func (p QuestionPool) Validate() error {
+	if p.Id == "" {
+		return fmt.Errorf("question pool has no ID")
+	}
+	if p.Draw < 1 {
+		return fmt.Errorf("question pool %s must draw at least one question", p.Id)
+	}
+	if p.MaxDifficulty > 0 && p.MinDifficulty > p.MaxDifficulty {
+		return fmt.Errorf("question pool %s has a minimum difficulty above its maximum", p.Id)
+	}
+	return nil
+}
+
+// drawQuestions picks the questions of each pool from its candidates. A
+// question is never drawn twice or when it is in exclude. Questions in seen
+// are only drawn once a pool has run out of other candidates. The draw
+// returns the questions together with the ID of the pool each came from.
+func drawQuestions(r *rand.Rand, pools []QuestionPool, candidates [][]Questioner, exclude, seen map[string]bool) ([]Questioner, []string, error) {
+	if len(candidates) != len(pools) {
+		return nil, nil, fmt.Errorf("expected candidates for %d pools, got %d", len(pools), len(candidates))
+	}
+
+	drawn := make(map[string]bool, len(exclude))
+	for id := range exclude {
+		drawn[id] = true
+	}
+
+	var (
+		questions []Questioner
+		poolIDs   []string
+	)
+	for i, pool := range pools {
+		var fresh, repeated []Questioner
+		for _, index := range r.Perm(len(candidates[i])) {
+			question := candidates[i][index]
+			switch {
+			case drawn[question.GetID()]:
+			case seen[question.GetID()]:
+				repeated = append(repeated, question)
+			default:
+				fresh = append(fresh, question)
+			}
+		}
+
+		available := append(fresh, repeated...)
+		picked := 0
+		for _, question := range available {
+			if picked == pool.Draw {
+				break
+			}
+			// A pool may list the same question twice
+			if drawn[question.GetID()] {
+				continue
+			}
+			drawn[question.GetID()] = true
+			questions = append(questions, question)
+			poolIDs = append(poolIDs, pool.Id)
+			picked++
+		}
+		if picked < pool.Draw {
+			return nil, nil, fmt.Errorf("question pool %s has %d questions available, need %d", pool.Id, picked, pool.Draw)
+		}
+	}
+
+	return questions, poolIDs, nil
+}
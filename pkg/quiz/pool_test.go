@@ -0,0 +1,140 @@
+package quiz
+
+import (
+	"strings"
+	"testing"
+)
+
+func createPoolQuestions(prefix string, n int) []Questioner {
+	questions := make([]Questioner, n)
+	for i := range questions {
+		questions[i] = &TrueFalse{
+			Id:         prefix + string(rune('a'+i)),
+			Prompt:     "Statement",
+			Difficulty: 1,
+			Answer:     true,
+		}
+	}
+	return questions
+}
+
+func TestQuestionPoolValidate(t *testing.T) {
+	if err := (QuestionPool{Id: "a", Draw: 2}).Validate(); err != nil {
+		t.Errorf("Expected a valid pool, got %v", err)
+	}
+	if err := (QuestionPool{Id: "a"}).Validate(); err == nil {
+		t.Errorf("Expected an error for a pool that draws nothing")
+	}
+	pool := QuestionPool{Id: "a", Draw: 1, QuestionFilter: QuestionFilter{MinDifficulty: 3, MaxDifficulty: 2}}
+	if err := pool.Validate(); err == nil {
+		t.Errorf("Expected an error for an empty difficulty range")
+	}
+
+	d := NewQuizDefinition("def1", "Pooled", nil)
+	d.Pools = []QuestionPool{{Id: "a", Draw: 1}, {Id: "a", Draw: 1}}
+	if err := d.Validate(); err == nil {
+		t.Errorf("Expected an error for duplicate pools")
+	}
+	d.Pools = d.Pools[:1]
+	if err := d.Validate(); err != nil {
+		t.Errorf("Expected a definition with only pools to be valid, got %v", err)
+	}
+}
+
+func TestNewDrawnAttempt(t *testing.T) {
+	d := NewQuizDefinition("def1", "Pooled", []string{"fixed"})
+	d.Pools = []QuestionPool{{Id: "a", Draw: 3}, {Id: "b", Draw: 2}}
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+
+	fixed := []Questioner{&TrueFalse{Id: "fixed", Prompt: "Fixed", Difficulty: 1, Answer: true}}
+	poolA := append(createPoolQuestions("a", 5), fixed[0])
+	// Pool b shares questions with pool a
+	poolB := append(createPoolQuestions("a", 3), createPoolQuestions("b", 2)...)
+	candidates := [][]Questioner{poolA, poolB}
+
+	quiz, err := NewDrawnAttempt("a1", d, fixed, candidates, nil, Options{Seed: 5})
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	if quiz.AmountOfQuestions() != 6 {
+		t.Fatalf("Expected 6 questions, got %d", quiz.AmountOfQuestions())
+	}
+
+	seen := make(map[string]bool)
+	for i, question := range quiz.GetQuestions() {
+		if seen[question.GetID()] {
+			t.Errorf("Expected no duplicates, got %s twice", question.GetID())
+		}
+		seen[question.GetID()] = true
+
+		wantPool := ""
+		switch {
+		case i == 0:
+		case i <= 3:
+			wantPool = "a"
+		default:
+			wantPool = "b"
+		}
+		if quiz.GetQuestionPool(i) != wantPool {
+			t.Errorf("Expected question %d from pool %q, got %q", i, wantPool, quiz.GetQuestionPool(i))
+		}
+	}
+
+	// The same seed draws the same questions
+	again, err := NewDrawnAttempt("a2", d, fixed, candidates, nil, Options{Seed: 5})
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	for i, question := range again.GetQuestions() {
+		if question.GetID() != quiz.GetQuestions()[i].GetID() {
+			t.Errorf("Expected question %s at %d, got %s", quiz.GetQuestions()[i].GetID(), i, question.GetID())
+		}
+	}
+
+	// NewAttempt cannot draw
+	if _, err := NewAttempt("a3", d, fixed); err == nil {
+		t.Errorf("Expected an error starting a pooled definition without drawing")
+	}
+}
+
+func TestNewDrawnAttemptTooFewQuestions(t *testing.T) {
+	d := NewQuizDefinition("def1", "Pooled", nil)
+	d.Pools = []QuestionPool{{Id: "a", Draw: 4}}
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+
+	_, err := NewDrawnAttempt("a1", d, nil, [][]Questioner{createPoolQuestions("a", 3)}, nil, Options{})
+	if err == nil || !strings.Contains(err.Error(), "pool a") {
+		t.Errorf("Expected an error naming pool a, got %v", err)
+	}
+}
+
+func TestNewDrawnAttemptMinimizesOverlap(t *testing.T) {
+	d := NewQuizDefinition("def1", "Pooled", nil)
+	d.Pools = []QuestionPool{{Id: "a", Draw: 3}}
+	d.MinimizeOverlap = true
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	candidates := [][]Questioner{createPoolQuestions("a", 5)}
+	previous := []string{"aa", "ab", "ac"}
+
+	for seed := int64(1); seed <= 20; seed++ {
+		quiz, err := NewDrawnAttempt("a1", d, nil, candidates, previous, Options{Seed: seed})
+		if err != nil {
+			t.Fatalf("Failed to start attempt: %v", err)
+		}
+		repeated := 0
+		for _, question := range quiz.GetQuestions() {
+			if question.GetID() < "ad" {
+				repeated++
+			}
+		}
+		if repeated != 1 {
+			t.Fatalf("Expected 1 repeated question with seed %d, got %d", seed, repeated)
+		}
+	}
+}
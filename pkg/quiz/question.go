@@ -1,17 +1,31 @@
 package quiz
 
 import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// MultiChoice asks the user to pick the one correct option. Explanations,
+// when given, line up with Options and explain why each option is right or
+// wrong; the one for the chosen option is returned as feedback. Options
+// listed in PinnedOptions keep their position when options are shuffled,
+// e.g. a trailing "All of the above".
 type MultiChoice struct {
-	Id         string        `json:"id"`
-	Prompt     string        `json:"prompt"`
-	Options    []string      `json:"options"`
-	Difficulty int           `json:"difficulty"`
-	Answer     string        `json:"answer"`
-	Hint       string        `json:"hint"`
-	TimeLimit  time.Duration `json:"timeLimit"`
+	Id            string        `json:"id"`
+	Prompt        string        `json:"prompt"`
+	Options       []string      `json:"options"`
+	PinnedOptions []int         `json:"pinnedOptions"`
+	Explanations  []string      `json:"explanations"`
+	Difficulty    int           `json:"difficulty"`
+	Answer        string        `json:"answer"`
+	Hint          string        `json:"hint"`
+	Hints         []string      `json:"hints"`
+	TimeLimit     time.Duration `json:"timeLimit"`
 }
 
 func (mc *MultiChoice) GetPrompt() string {
@@ -26,6 +40,18 @@ func (mc *MultiChoice) CheckAnswer(answer string) bool {
 	return answer == mc.Answer
 }
 
+// Evaluate grades the answer and returns the explanation for the chosen option.
+func (mc *MultiChoice) Evaluate(answer Answer) GradeResult {
+	result := checkResult(mc, answer)
+	for i, option := range mc.Options {
+		if option == answer.Encode() && i < len(mc.Explanations) {
+			result.Feedback = mc.Explanations[i]
+			break
+		}
+	}
+	return result
+}
+
 func (mc *MultiChoice) GetDifficulty() int {
 	return mc.Difficulty
 }
@@ -34,13 +60,37 @@ func (mc *MultiChoice) GetTimeLimit() time.Duration {
 	return mc.TimeLimit
 }
 
+func (mc *MultiChoice) GetHints() []string {
+	return hintList(mc.Hint, mc.Hints)
+}
+
+func (mc *MultiChoice) GetOptions() []string {
+	return mc.Options
+}
+
+func (mc *MultiChoice) ShuffleOptions(r *rand.Rand) []int {
+	return shuffleOptions(r, len(mc.Options), mc.PinnedOptions)
+}
+
+// CanonicalAnswer returns the answer unchanged, since MultiChoice answers
+// name the chosen option rather than its position.
+func (mc *MultiChoice) CanonicalAnswer(answer Answer, order []int) Answer {
+	return answer
+}
+
+// TrueFalse asks whether a statement is true. Answers are read with
+// ParseTrueFalse. TrueExplanation and FalseExplanation are returned as
+// feedback to users who answered true or false respectively.
 type TrueFalse struct {
-	Id         string        `json:"id"`
-	Prompt     string        `json:"prompt"`
-	Difficulty int           `json:"difficulty"`
-	Answer     bool          `json:"answer"`
-	Hint       string        `json:"hint"`
-	TimeLimit  time.Duration `json:"timeLimit"`
+	Id               string        `json:"id"`
+	Prompt           string        `json:"prompt"`
+	Difficulty       int           `json:"difficulty"`
+	Answer           bool          `json:"answer"`
+	TrueExplanation  string        `json:"trueExplanation"`
+	FalseExplanation string        `json:"falseExplanation"`
+	Hint             string        `json:"hint"`
+	Hints            []string      `json:"hints"`
+	TimeLimit        time.Duration `json:"timeLimit"`
 }
 
 func (tf *TrueFalse) GetPrompt() string {
@@ -52,7 +102,21 @@ func (tf *TrueFalse) GetID() string {
 }
 
 func (tf *TrueFalse) CheckAnswer(answer string) bool {
-	return answer == "true"
+	value, ok := ParseTrueFalse(answer)
+	return ok && value == tf.Answer
+}
+
+// Evaluate grades the answer and returns the explanation for the chosen value.
+func (tf *TrueFalse) Evaluate(answer Answer) GradeResult {
+	result := checkResult(tf, answer)
+	if value, ok := ParseTrueFalse(answer.Encode()); ok {
+		if value {
+			result.Feedback = tf.TrueExplanation
+		} else {
+			result.Feedback = tf.FalseExplanation
+		}
+	}
+	return result
 }
 
 func (tf *TrueFalse) GetDifficulty() int {
@@ -63,25 +127,128 @@ func (tf *TrueFalse) GetTimeLimit() time.Duration {
 	return tf.TimeLimit
 }
 
+func (tf *TrueFalse) GetHints() []string {
+	return hintList(tf.Hint, tf.Hints)
+}
+
+// trueFalseWords maps the accepted spellings of true and false, including
+// common translations, to their value.
+var trueFalseWords = map[string]bool{
+	"true": true, "t": true, "yes": true, "y": true, "1": true, "on": true,
+	"false": false, "f": false, "no": false, "n": false, "0": false, "off": false,
+	"vrai": true, "oui": true, "faux": false, "non": false,
+	"wahr": true, "ja": true, "falsch": false, "nein": false,
+	"verdadero": true, "sí": true, "si": true, "falso": false,
+	"vero": true, "waar": true, "onwaar": false, "nee": false,
+}
+
+// ParseTrueFalse reads a true or false answer, ignoring case and surrounding
+// whitespace. The second result is false if the answer is not recognised.
+func ParseTrueFalse(answer string) (bool, bool) {
+	value, ok := trueFalseWords[strings.ToLower(strings.TrimSpace(answer))]
+	return value, ok
+}
+
+// FillIn asks for a typed answer. Answer and CorrectAnswers list the accepted
+// answers, compared using the embedded TextMatching rules. Patterns are
+// regular expressions that must match the whole trimmed answer; they are
+// compiled once, when the question is validated or first checked, and
+// recompiled if Patterns or IgnoreCase change.
 type FillIn struct {
-	Id         string        `json:"id"`
-	Prompt     string        `json:"prompt"`
-	Difficulty int           `json:"difficulty"`
-	Answer     string        `json:"answer"`
-	Hint       string        `json:"hint"`
-	TimeLimit  time.Duration `json:"timeLimit"`
+	Id             string   `json:"id"`
+	Prompt         string   `json:"prompt"`
+	Difficulty     int      `json:"difficulty"`
+	Answer         string   `json:"answer"`
+	CorrectAnswers []string `json:"correctAnswers"`
+	Patterns       []string `json:"patterns"`
+	TextMatching
+	Hint      string        `json:"hint"`
+	Hints     []string      `json:"hints"`
+	TimeLimit time.Duration `json:"timeLimit"`
+
+	compiled *compiledPatterns
+}
+
+// compiledPatterns caches the regular expressions compiled from a FillIn's
+// Patterns with the case setting they were compiled for.
+type compiledPatterns struct {
+	sources    []string
+	ignoreCase bool
+	regexps    []*regexp.Regexp
 }
 
 func (fi *FillIn) GetPrompt() string {
 	return fi.Prompt
 }
 
+// Validate checks that every pattern is a valid regular expression and
+// compiles them for CheckAnswer.
+func (fi *FillIn) Validate() error {
+	_, err := fi.patterns()
+	return err
+}
+
+// patterns returns the compiled Patterns, compiling them if they changed
+// since they were last compiled.
+func (fi *FillIn) patterns() ([]*regexp.Regexp, error) {
+	if c := fi.compiled; c != nil && c.ignoreCase == fi.IgnoreCase && slices.Equal(c.sources, fi.Patterns) {
+		return c.regexps, nil
+	}
+
+	regexps := make([]*regexp.Regexp, 0, len(fi.Patterns))
+	for _, pattern := range fi.Patterns {
+		source := "^(?:" + pattern + ")$"
+		if fi.IgnoreCase {
+			source = "(?i)" + source
+		}
+		re, err := regexp.Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+		regexps = append(regexps, re)
+	}
+	fi.compiled = &compiledPatterns{
+		sources:    slices.Clone(fi.Patterns),
+		ignoreCase: fi.IgnoreCase,
+		regexps:    regexps,
+	}
+	return regexps, nil
+}
+
 func (fi *FillIn) GetID() string {
 	return fi.Id
 }
 
 func (fi *FillIn) CheckAnswer(answer string) bool {
-	return answer == fi.Answer
+	for _, accepted := range fi.AcceptedAnswers() {
+		if fi.Matches(answer, accepted) {
+			return true
+		}
+	}
+
+	// A question with an invalid pattern accepts no answers through its
+	// patterns; Validate reports the error
+	regexps, err := fi.patterns()
+	if err != nil {
+		return false
+	}
+	for _, re := range regexps {
+		if re.MatchString(strings.TrimSpace(answer)) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptedAnswers returns Answer followed by CorrectAnswers, skipping blanks.
+func (fi *FillIn) AcceptedAnswers() []string {
+	accepted := make([]string, 0, len(fi.CorrectAnswers)+1)
+	for _, a := range append([]string{fi.Answer}, fi.CorrectAnswers...) {
+		if strings.TrimSpace(a) != "" {
+			accepted = append(accepted, a)
+		}
+	}
+	return accepted
 }
 
 func (fi *FillIn) GetDifficulty() int {
@@ -92,10 +259,70 @@ func (fi *FillIn) GetTimeLimit() time.Duration {
 	return fi.TimeLimit
 }
 
+func (fi *FillIn) GetHints() []string {
+	return hintList(fi.Hint, fi.Hints)
+}
+
 type Questioner interface {
 	GetID() string
 	GetPrompt() string
 	GetDifficulty() int
 	GetTimeLimit() time.Duration
+	GetHints() []string
 	CheckAnswer(answer string) bool
 }
+
+// GradingMode controls whether a question awards credit only for a fully
+// correct answer or a fraction of it for a partially correct one.
+type GradingMode string
+
+const (
+	ALL_OR_NOTHING GradingMode = "ALL_OR_NOTHING"
+	PARTIAL_CREDIT GradingMode = "PARTIAL_CREDIT"
+)
+
+// PartialScorer is implemented by questions that can award part of their
+// credit. PartialScore returns a fraction between 0 and 1.
+type PartialScorer interface {
+	PartialScore(answer string) float64
+}
+
+// EncodeIndices formats a set of option indices as an answer string, e.g. "0,2".
+func EncodeIndices(indices []int) string {
+	parts := make([]string, len(indices))
+	for i, idx := range indices {
+		parts[i] = strconv.Itoa(idx)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseIndices parses an answer string produced by EncodeIndices. An empty
+// string is a valid answer meaning nothing was selected.
+func ParseIndices(answer string) ([]int, error) {
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return []int{}, nil
+	}
+	parts := strings.Split(answer, ",")
+	indices := make([]int, 0, len(parts))
+	for _, part := range parts {
+		idx, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %q: %v", part, err)
+		}
+		indices = append(indices, idx)
+	}
+	return indices, nil
+}
+
+// indexSet converts indices into a set, rejecting any outside [0, n).
+func indexSet(indices []int, n int) (map[int]bool, bool) {
+	set := make(map[int]bool, len(indices))
+	for _, idx := range indices {
+		if idx < 0 || idx >= n {
+			return nil, false
+		}
+		set[idx] = true
+	}
+	return set, true
+}
@@ -84,6 +84,85 @@ func TestTrueFalse(t *testing.T) {
 	}
 }
 
+func TestTrueFalseFalseStatement(t *testing.T) {
+	tf := &TrueFalse{Id: "tf2", Prompt: "The sun orbits the earth", Difficulty: 1, Answer: false}
+
+	for _, answer := range []string{"false", "False", " f ", "no", "0", "faux", "nein"} {
+		if !tf.CheckAnswer(answer) {
+			t.Errorf("Expected %q to be accepted", answer)
+		}
+	}
+	for _, answer := range []string{"true", "yes", "1", "maybe", ""} {
+		if tf.CheckAnswer(answer) {
+			t.Errorf("Expected %q to be rejected", answer)
+		}
+	}
+}
+
+func TestParseTrueFalse(t *testing.T) {
+	tests := []struct {
+		answer string
+		value  bool
+		ok     bool
+	}{
+		{"TRUE", true, true},
+		{"y", true, true},
+		{"oui", true, true},
+		{"off", false, true},
+		{"falso", false, true},
+		{"perhaps", false, false},
+	}
+	for _, tt := range tests {
+		value, ok := ParseTrueFalse(tt.answer)
+		if value != tt.value || ok != tt.ok {
+			t.Errorf("ParseTrueFalse(%q): expected (%v, %v), got (%v, %v)", tt.answer, tt.value, tt.ok, value, ok)
+		}
+	}
+}
+
+func TestChoiceExplanations(t *testing.T) {
+	mc := &MultiChoice{
+		Id:           "mc1",
+		Prompt:       "What is 2+2?",
+		Options:      []string{"3", "4", "5"},
+		Explanations: []string{"One too few", "Two pairs make four", "One too many"},
+		Difficulty:   1,
+		Answer:       "4",
+	}
+
+	result := Evaluate(mc, TextAnswer("5"))
+	if result.IsCorrect {
+		t.Error("Expected '5' to be incorrect")
+	}
+	if result.Feedback != "One too many" {
+		t.Errorf("Expected feedback 'One too many', got '%s'", result.Feedback)
+	}
+	result = Evaluate(mc, TextAnswer("4"))
+	if !result.IsCorrect || result.Feedback != "Two pairs make four" {
+		t.Errorf("Expected correct result with explanation, got %+v", result)
+	}
+	if Evaluate(mc, TextAnswer("7")).Feedback != "" {
+		t.Error("Expected no feedback for an answer that is not an option")
+	}
+
+	tf := &TrueFalse{
+		Id:               "tf1",
+		Prompt:           "The sun orbits the earth",
+		Difficulty:       1,
+		Answer:           false,
+		TrueExplanation:  "The earth orbits the sun",
+		FalseExplanation: "Right, the earth orbits the sun",
+	}
+	result = Evaluate(tf, TextAnswer("yes"))
+	if result.IsCorrect || result.Feedback != "The earth orbits the sun" {
+		t.Errorf("Expected incorrect result with true explanation, got %+v", result)
+	}
+	result = Evaluate(tf, TextAnswer("false"))
+	if !result.IsCorrect || result.Feedback != "Right, the earth orbits the sun" {
+		t.Errorf("Expected correct result with false explanation, got %+v", result)
+	}
+}
+
 func TestFillIn(t *testing.T) {
 	fi := &FillIn{
 		Id:         "fi1",
@@ -121,6 +200,97 @@ func TestFillIn(t *testing.T) {
 	if fi.CheckAnswer("London") {
 		t.Error("Expected incorrect answer 'London' to return false")
 	}
+	if fi.CheckAnswer("paris") {
+		t.Error("Expected answers to be case-sensitive by default")
+	}
+}
+
+func TestFillInAcceptedAnswers(t *testing.T) {
+	fi := &FillIn{
+		Id:             "fi2",
+		Prompt:         "Who painted the Mona Lisa?",
+		Answer:         "Leonardo da Vinci",
+		CorrectAnswers: []string{"da Vinci", "Leonardo"},
+		TextMatching:   TextMatching{IgnoreCase: true},
+	}
+
+	for _, answer := range []string{"Leonardo da Vinci", "leonardo  DA vinci", " Da Vinci ", "Leonardo"} {
+		if !fi.CheckAnswer(answer) {
+			t.Errorf("Expected '%s' to be accepted", answer)
+		}
+	}
+	if fi.CheckAnswer("Michelangelo") {
+		t.Error("Expected 'Michelangelo' to be rejected")
+	}
+
+	fi.IgnoreCase = false
+	if fi.CheckAnswer("leonardo") {
+		t.Error("Expected case sensitive question to reject 'leonardo'")
+	}
+
+	accepted := fi.AcceptedAnswers()
+	if len(accepted) != 3 || accepted[0] != "Leonardo da Vinci" {
+		t.Errorf("Expected 3 accepted answers starting with Answer, got %v", accepted)
+	}
+}
+
+func TestFillInNormalization(t *testing.T) {
+	fi := &FillIn{
+		Id:     "fi3",
+		Prompt: "Name the Mexican dish made with a corn tortilla",
+		Answer: "Enchilada!",
+		TextMatching: TextMatching{
+			IgnoreCase:        true,
+			IgnorePunctuation: true,
+			IgnoreDiacritics:  true,
+			MaxEditDistance:   1,
+		},
+	}
+
+	for _, answer := range []string{"enchilada", "Énchilada", "enchilda", "enchilada."} {
+		if !fi.CheckAnswer(answer) {
+			t.Errorf("Expected '%s' to be accepted", answer)
+		}
+	}
+	if fi.CheckAnswer("enchlda") {
+		t.Error("Expected answer two edits away to be rejected")
+	}
+}
+
+func TestFillInPatterns(t *testing.T) {
+	fi := &FillIn{
+		Id:           "fi4",
+		Prompt:       "Give an example of a valid IPv4 loopback address",
+		Patterns:     []string{`127(\.\d{1,3}){3}`, `localhost`},
+		TextMatching: TextMatching{IgnoreCase: true},
+	}
+
+	if !fi.CheckAnswer(" 127.0.0.1 ") {
+		t.Error("Expected pattern to match '127.0.0.1'")
+	}
+	if !fi.CheckAnswer("LOCALHOST") {
+		t.Error("Expected pattern to match case insensitively")
+	}
+	if fi.CheckAnswer("127.0.0.1/8") {
+		t.Error("Expected pattern to match the whole answer")
+	}
+	if err := fi.Validate(); err != nil {
+		t.Errorf("Expected valid patterns, got %v", err)
+	}
+
+	// Changing the case setting recompiles the patterns
+	fi.IgnoreCase = false
+	if fi.CheckAnswer("LOCALHOST") {
+		t.Error("Expected pattern to match case sensitively")
+	}
+
+	fi.Patterns = append(fi.Patterns, `[`)
+	if err := fi.Validate(); err == nil {
+		t.Error("Expected error for an invalid pattern")
+	}
+	if fi.CheckAnswer("[") || fi.CheckAnswer("127.0.0.1") {
+		t.Error("Expected a question with an invalid pattern to accept no answers through its patterns")
+	}
 }
 
 func TestQuestionerInterface(t *testing.T) {
@@ -1,6 +1,28 @@
 package quiz
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// ErrQuizCompleted is returned when answering a quiz that has no questions
+// left to answer.
+var ErrQuizCompleted = errors.New("quiz is already completed")
+
+// ErrQuizExpired is returned when answering a quiz whose time limit has passed.
+var ErrQuizExpired = errors.New("quiz time limit has passed")
+
+// ErrQuestionExpired is returned when an answer arrives after the question's
+// time limit and the definition rejects late answers.
+var ErrQuestionExpired = errors.New("question time limit has passed")
+
+// ErrNoTriesLeft is returned, usually wrapped in a TransitionError, when
+// answering a question that was already answered correctly or has used all
+// of its tries.
+var ErrNoTriesLeft = errors.New("no tries left for this question")
 
 type QuizStatus string
 
@@ -11,44 +33,143 @@ const (
 	FINISHED        QuizStatus = "FINISHED"
 	SAVED           QuizStatus = "SAVED"
 	QUIT            QuizStatus = "QUIT"
+	// AWAITING_GRADING means every question was answered but some responses
+	// still need to be graded by a person.
+	AWAITING_GRADING QuizStatus = "AWAITING_GRADING"
+	// EXPIRED means the quiz's time limit passed before it was finished.
+	EXPIRED QuizStatus = "EXPIRED"
 )
 
+// QuestionAttempt is what the user submitted for one question: the encoded
+// answer, when it was submitted and how long they spent on the question.
+type QuestionAttempt struct {
+	QuestionID  string
+	Answer      string
+	SubmittedAt time.Time
+	TimeSpent   time.Duration
+}
+
 type QuestionResult struct {
 	QuestionID string
-	Correct    bool
-	TimeTaken  time.Duration
+	// QuestionIndex is the position of the question in the quiz, which tells
+	// apart answers to a question that appears more than once.
+	QuestionIndex int
+	Correct       bool
+	TimeTaken     time.Duration
+	// Try counts the answers given to the question so far, starting at 1.
+	// Only the latest try of a question counts towards the score.
+	Try int
+	// Pending is set while a manually graded response waits for a grader.
+	Pending bool
+	// Late is set when the answer arrived after the question's time limit
+	// and earned no credit.
+	Late bool
+	// HintsUsed is how many of the question's hints were revealed before
+	// this try was submitted.
+	HintsUsed int
+	Attempt   QuestionAttempt
+	Grade     GradeResult
 }
 
+// Quiz is one attempt of a QuizDefinition, holding the state of a single run
+// through its questions. The scoring strategy and settings of the definition
+// are copied when the attempt starts and kept with it, so the attempt
+// follows the rules it was started under.
 type Quiz struct {
 	Id              string
+	definition      *QuizDefinition
+	scoring         ScoringStrategy
+	settings        AttemptSettings
+	userIDs         []string
 	questions       []Questioner
+	questionPools   []string
+	optionOrders    [][]int
+	candidates      []Questioner
+	trace           []AdaptiveStep
+	flagged         map[int]bool
+	hintsUsed       map[int]int
+	timeSpent       map[int]time.Duration
 	currentIndex    int
-	score           int
+	score           float64
 	completed       bool
 	status          QuizStatus
+	clock           Clock
+	seed            int64
+	rand            *rand.Rand
 	startTime       time.Time
 	creationDate    time.Time
+	presentedAt     time.Time
+	pausedAt        time.Time
+	pausedTime      time.Duration
 	timeTaken       time.Duration
 	correctCount    int
 	questionHistory []QuestionResult
 }
 
+// NewQuiz starts an attempt of the given questions. It is described by an
+// implicit published definition that belongs to the attempt alone, see
+// QuizDefinition.AttemptID; use NewAttempt to start an attempt of a stored
+// definition.
 func NewQuiz(id string, questions []Questioner) *Quiz {
+	return NewQuizWithOptions(id, questions, Options{})
+}
+
+// NewQuizWithOptions starts an attempt of the given questions like NewQuiz,
+// reading the time and random numbers as configured by opts.
+func NewQuizWithOptions(id string, questions []Questioner, opts Options) *Quiz {
+	clock := opts.clock()
+	seed := opts.seed()
+	now := clock.Now()
+	definition := adHocDefinition(id, questions, opts)
 	return &Quiz{
 		Id:              id,
+		definition:      definition,
+		scoring:         definition.Strategy(),
+		settings:        definition.Settings(),
 		questions:       questions,
+		flagged:         make(map[int]bool),
+		hintsUsed:       make(map[int]int),
+		timeSpent:       make(map[int]time.Duration),
 		currentIndex:    0,
 		score:           0,
 		completed:       false,
 		status:          STARTED,
-		startTime:       time.Now(),
-		creationDate:    time.Now(),
+		clock:           clock,
+		seed:            seed,
+		rand:            NewRand(seed),
+		startTime:       now,
+		creationDate:    now,
+		presentedAt:     now,
 		timeTaken:       0,
 		correctCount:    0,
 		questionHistory: make([]QuestionResult, 0),
 	}
 }
 
+// follow makes the attempt one of definition, scored and run by the
+// definition's current strategy and settings.
+func (q *Quiz) follow(definition *QuizDefinition) {
+	q.definition = definition
+	q.scoring = definition.Strategy()
+	q.settings = definition.Settings()
+}
+
+// GetSettings returns the rules the attempt follows, as copied from its
+// definition when it started.
+func (q *Quiz) GetSettings() AttemptSettings {
+	return q.settings
+}
+
+// SetClock makes the attempt read the time from clock from now on.
+func (q *Quiz) SetClock(clock Clock) {
+	q.clock = clock
+}
+
+// GetSeed returns the seed of the attempt's random source.
+func (q *Quiz) GetSeed() int64 {
+	return q.seed
+}
+
 func (q *Quiz) AmountOfQuestions() int {
 	return len(q.questions)
 }
@@ -61,47 +182,359 @@ func (q *Quiz) CurrentQuestion() Questioner {
 }
 
 func (q *Quiz) NextQuestion() bool {
+	now := q.clock.Now()
+	if q.expire(now) || q.checkTransition(AWAITING_ANSWER) != nil {
+		return false
+	}
+	// Adaptive attempts pick their next question once the current one is left
+	if q.IsAdaptive() && q.currentIndex == len(q.questions)-1 {
+		q.adapt()
+	}
 	if q.currentIndex >= len(q.questions)-1 {
-		q.completed = true
-		q.status = FINISHED
-		q.timeTaken = time.Since(q.startTime)
+		// Attempts that can be navigated are finished with SubmitAll
+		if !q.canNavigate() {
+			q.finish(now)
+		}
 		return false
 	}
-	q.currentIndex++
-	q.status = AWAITING_ANSWER
+	q.present(q.currentIndex+1, now)
 	return true
 }
 
+// present shows the question at index from now on, keeping the time spent on
+// the question it leaves. The attempt is ANSWERED if the question already
+// has an answer and AWAITING_ANSWER otherwise.
+func (q *Quiz) present(index int, now time.Time) {
+	q.timeSpent[q.currentIndex] += now.Sub(q.presentedAt)
+	q.currentIndex = index
+	q.presentedAt = now
+	q.status = AWAITING_ANSWER
+	if q.latestResult(index) != nil {
+		q.status = ANSWERED
+	}
+}
+
+// questionTime returns how long the current question has been shown at now,
+// over all the times it was presented.
+func (q *Quiz) questionTime(now time.Time) time.Duration {
+	return q.timeSpent[q.currentIndex] + now.Sub(q.presentedAt)
+}
+
+// finish completes the attempt at now.
+func (q *Quiz) finish(now time.Time) {
+	q.completed = true
+	q.status = FINISHED
+	if q.HasPendingGrades() {
+		q.status = AWAITING_GRADING
+	}
+	q.timeTaken = q.activeTime(now)
+}
+
+// CheckExpiry ends the quiz if its time limit has passed and reports whether
+// it has expired. Answering, navigating, pausing and quitting check expiry
+// themselves; the getters report the attempt as it was last changed, so call
+// CheckExpiry first to see whether a timed attempt ran out in the meantime.
+func (q *Quiz) CheckExpiry() bool {
+	return q.expire(q.clock.Now())
+}
+
+// expire ends the quiz if its time limit has passed at now and reports
+// whether it has expired.
+func (q *Quiz) expire(now time.Time) bool {
+	deadline, ok := q.Deadline()
+	if !q.completed && q.status.CanTransition(EXPIRED) && ok && now.After(deadline) {
+		q.completed = true
+		q.status = EXPIRED
+		q.timeTaken = q.settings.TimeLimit
+	}
+	return q.status == EXPIRED
+}
+
+// Deadline returns when the quiz's time limit passes. The second result is
+// false if the quiz has no time limit.
+func (q *Quiz) Deadline() (time.Time, bool) {
+	if q.settings.TimeLimit <= 0 {
+		return time.Time{}, false
+	}
+	return q.startTime.Add(q.settings.TimeLimit + q.pausedTime), true
+}
+
+// RemainingTime returns how long is left before the quiz's time limit
+// passes. The second result is false if the quiz has no time limit.
+func (q *Quiz) RemainingTime() (time.Duration, bool) {
+	deadline, ok := q.Deadline()
+	if !ok {
+		return 0, false
+	}
+	if remaining := deadline.Sub(q.runningNow()); remaining > 0 {
+		return remaining, true
+	}
+	return 0, true
+}
+
+// QuestionRemainingTime returns how long is left to answer the current
+// question. The second result is false if the question has no time limit.
+func (q *Quiz) QuestionRemainingTime() (time.Duration, bool) {
+	current := q.CurrentQuestion()
+	if current == nil || current.GetTimeLimit() <= 0 {
+		return 0, false
+	}
+	if remaining := current.GetTimeLimit() - q.questionTime(q.runningNow()); remaining > 0 {
+		return remaining, true
+	}
+	return 0, true
+}
+
+// SubmitAnswer grades a string answer for the current question and reports
+// whether it was fully correct.
 func (q *Quiz) SubmitAnswer(answer string) bool {
+	result, err := q.Submit(TextAnswer(answer))
+	return err == nil && result.IsCorrect
+}
+
+// Submit grades answer for the current question, records the outcome in the
+// question history and adds the awarded points to the score. When the
+// definition allows more than one try, a wrong answer can be replaced by
+// submitting again; the new try's points, reduced by the definition's
+// RetryDecay, replace those of the previous try.
+//
+// Time on a question adds up every time it was presented. Answers after
+// the question's time limit earn no credit, or are rejected with
+// ErrQuestionExpired if the definition's LatePolicy is REJECT_LATE. Once the
+// quiz's own time limit passes it becomes EXPIRED and answers are rejected
+// with ErrQuizExpired.
+func (q *Quiz) Submit(answer Answer) (GradeResult, error) {
+	now := q.clock.Now()
+	if q.expire(now) {
+		return GradeResult{}, ErrQuizExpired
+	}
 	if q.completed || q.currentIndex >= len(q.questions) {
-		return false
+		return GradeResult{}, ErrQuizCompleted
+	}
+	if err := q.checkTransition(ANSWERED); err != nil {
+		return GradeResult{}, err
 	}
 
 	current := q.CurrentQuestion()
 	if current == nil {
-		return false
+		return GradeResult{}, ErrQuizCompleted
+	}
+	if q.RemainingTries() == 0 {
+		return GradeResult{}, ErrNoTriesLeft
 	}
 
-	startTime := time.Now()
-	isCorrect := current.CheckAnswer(answer)
-	timeTaken := time.Since(startTime)
+	timeSpent := q.questionTime(now)
+	late := current.GetTimeLimit() > 0 && timeSpent > current.GetTimeLimit()
+	if late && q.settings.LatePolicy == REJECT_LATE {
+		return GradeResult{}, ErrQuestionExpired
+	}
 
-	q.questionHistory = append(q.questionHistory, QuestionResult{
-		QuestionID: current.GetID(),
-		Correct:    isCorrect,
-		TimeTaken:  timeTaken,
-	})
+	try := 1
+	previous := q.latestResult(q.currentIndex)
+	if previous != nil {
+		try = previous.Try + 1
+		q.score -= previous.Grade.ScoreAwarded
+		if previous.Correct {
+			q.correctCount--
+		}
+	}
+
+	answer = q.canonicalAnswer(q.currentIndex, answer)
+	hintsUsed := q.hintsUsed[q.currentIndex]
+	result := q.award(current, Evaluate(current, answer), try, hintsUsed, timeSpent)
+	if late {
+		result = GradeResult{
+			MaxScore: result.MaxScore,
+			Feedback: "The answer was submitted after the time limit",
+		}
+	}
 
-	if isCorrect {
-		q.score += current.GetDifficulty()
+	entry := QuestionResult{
+		QuestionID:    current.GetID(),
+		QuestionIndex: q.currentIndex,
+		Correct:       result.IsCorrect,
+		TimeTaken:     timeSpent,
+		Try:           try,
+		Pending:       result.Pending,
+		Late:          late,
+		HintsUsed:     hintsUsed,
+		Attempt: QuestionAttempt{
+			QuestionID:  current.GetID(),
+			Answer:      answer.Encode(),
+			SubmittedAt: now,
+			TimeSpent:   timeSpent,
+		},
+		Grade: result,
+	}
+	q.questionHistory = append(q.questionHistory, entry)
+
+	q.score += result.ScoreAwarded
+	if result.IsCorrect {
 		q.correctCount++
 	}
 
 	q.status = ANSWERED
-	return isCorrect
+	return result, nil
+}
+
+// worth returns the points question is worth under the attempt's scoring
+// strategy, bonuses aside.
+func (q *Quiz) worth(question Questioner) float64 {
+	return q.scoring.Score(question, GradeResult{MaxScore: float64(question.GetDifficulty())}, 0).MaxScore
+}
+
+// award scores grade with the attempt's scoring strategy and reduces the
+// points it awards by the try credit and hint penalty.
+func (q *Quiz) award(question Questioner, grade GradeResult, try, hintsUsed int, timeSpent time.Duration) GradeResult {
+	grade = q.scoring.Score(question, grade, timeSpent)
+	if grade.ScoreAwarded > 0 {
+		grade.ScoreAwarded *= q.settings.TryCredit(try)
+		grade.ScoreAwarded = q.settings.HintPenalty.Apply(grade.ScoreAwarded, grade.MaxScore, hintsUsed)
+	}
+	return grade
 }
 
-func (q *Quiz) TotalPoints() int {
+// RemainingTries returns how many more times the current question may be
+// answered. Questions that were answered correctly or need manual grading
+// have no tries left once answered.
+func (q *Quiz) RemainingTries() int {
+	current := q.CurrentQuestion()
+	if q.completed || current == nil {
+		return 0
+	}
+
+	latest := q.latestResult(q.currentIndex)
+	if latest == nil {
+		return q.settings.AllowedTries()
+	}
+	if latest.Correct || needsManualGrading(current) {
+		return 0
+	}
+	remaining := q.settings.AllowedTries() - latest.Try
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// latestResult returns the most recent result for the question at index, or
+// nil if it has not been answered.
+func (q *Quiz) latestResult(index int) *QuestionResult {
+	for i := len(q.questionHistory) - 1; i >= 0; i-- {
+		if q.questionHistory[i].QuestionIndex == index {
+			return &q.questionHistory[i]
+		}
+	}
+	return nil
+}
+
+// GradeResponse records a grader's points and feedback for the pending
+// response to the question at index. Points range from 0 to what the question is worth under the
+// attempt's scoring strategy, as reported in the result's MaxScore, and
+// awarding all of them marks the response correct. Try credit, hint
+// penalties and the strategy's deductions and bonuses then apply as for any
+// other answer. Once the last pending response of a completed quiz is
+// graded the quiz becomes FINISHED.
+func (q *Quiz) GradeResponse(index int, points float64, feedback string) error {
+	if index < 0 || index >= len(q.questions) {
+		return ErrNoSuchQuestion
+	}
+	question := q.questions[index]
+	maxScore := q.worth(question)
+	if points < 0 || points > maxScore {
+		return fmt.Errorf("points must be between 0 and %v, got %v", maxScore, points)
+	}
+
+	// Strategies score grades worth the question's difficulty
+	difficulty := float64(question.GetDifficulty())
+	share := 1.0
+	if maxScore > 0 {
+		share = points / maxScore
+	}
+
+	for i := range q.questionHistory {
+		result := &q.questionHistory[i]
+		if result.QuestionIndex != index || !result.Pending {
+			continue
+		}
+
+		result.Pending = false
+		result.Correct = points == maxScore
+		result.Grade = q.award(question, GradeResult{
+			IsCorrect:    result.Correct,
+			ScoreAwarded: share * difficulty,
+			MaxScore:     difficulty,
+			Feedback:     feedback,
+		}, result.Try, result.HintsUsed, result.TimeTaken)
+		q.score += result.Grade.ScoreAwarded
+		if result.Correct {
+			q.correctCount++
+		}
+
+		if q.status == AWAITING_GRADING && !q.HasPendingGrades() {
+			q.status = FINISHED
+		}
+		return nil
+	}
+
+	return fmt.Errorf("question %d has no pending response", index)
+}
+
+// Regrade evaluates every stored answer again against the current questions,
+// for example after an answer key was fixed, and adjusts the score. Manually
+// graded responses keep the grade given by the grader, and late answers and
+// results recorded without their answer are left alone. It returns the
+// number of results whose score or correctness changed.
+func (q *Quiz) Regrade() int {
+	questions := make(map[string]Questioner, len(q.questions))
+	for _, question := range q.questions {
+		questions[question.GetID()] = question
+	}
+
+	changed := 0
+	for i := range q.questionHistory {
+		result := &q.questionHistory[i]
+		question, ok := questions[result.QuestionID]
+		if !ok || needsManualGrading(question) || result.Late || result.Attempt.SubmittedAt.IsZero() {
+			continue
+		}
+
+		grade := q.award(question, Evaluate(question, TextAnswer(result.Attempt.Answer)), result.Try, result.HintsUsed, result.TimeTaken)
+		if grade.IsCorrect == result.Correct && grade.ScoreAwarded == result.Grade.ScoreAwarded {
+			result.Grade.Feedback = grade.Feedback
+			continue
+		}
+
+		changed++
+		if q.latestResult(result.QuestionIndex) == result {
+			q.score += grade.ScoreAwarded - result.Grade.ScoreAwarded
+			if result.Correct {
+				q.correctCount--
+			}
+			if grade.IsCorrect {
+				q.correctCount++
+			}
+		}
+		result.Correct = grade.IsCorrect
+		result.Grade = grade
+	}
+	return changed
+}
+
+// HasPendingGrades reports whether any response still needs manual grading.
+func (q *Quiz) HasPendingGrades() bool {
+	for _, result := range q.questionHistory {
+		if result.Pending {
+			return true
+		}
+	}
+	return false
+}
+
+// TotalPoints returns the raw sum of the points awarded for the latest try
+// of each question, deductions included. It is what attempts store, and it
+// may be negative under negative marking. GetScore is the score to report.
+func (q *Quiz) TotalPoints() float64 {
 	return q.score
 }
 
@@ -117,11 +550,29 @@ func (q *Quiz) GetStatus() QuizStatus {
 	return q.status
 }
 
+// GetTimeTaken returns how long the attempt ran, or has run so far if it is
+// still going. It never exceeds the quiz's time limit.
 func (q *Quiz) GetTimeTaken() time.Duration {
-	if q.completed {
+	if q.completed || q.status == QUIT {
 		return q.timeTaken
 	}
-	return time.Since(q.startTime)
+	taken := q.activeTime(q.clock.Now())
+	if limit := q.settings.TimeLimit; limit > 0 && taken > limit {
+		return limit
+	}
+	return taken
+}
+
+// GetPresentedAt returns when the current question was presented.
+func (q *Quiz) GetPresentedAt() time.Time {
+	return q.presentedAt
+}
+
+// GetTimeSpent returns how long the question at index was shown before it
+// was last presented. The current question's time since GetPresentedAt is
+// added to it.
+func (q *Quiz) GetTimeSpent(index int) time.Duration {
+	return q.timeSpent[index]
 }
 
 func (q *Quiz) GetCreationDate() time.Time {
@@ -140,8 +591,18 @@ func (q *Quiz) GetCurrentIndex() int {
 	return q.currentIndex
 }
 
-func (q *Quiz) GetScore() int {
-	return q.score
+// GetScore returns the attempt's score: TotalPoints as adjusted by the
+// scoring strategy's Total, e.g. floored at zero by NegativeMarking with
+// FloorAtZero. Results and pass marks use this score.
+func (q *Quiz) GetScore() float64 {
+	return q.scoring.Total(q.score)
+}
+
+// GetScoring returns the strategy that scores the attempt. It is taken from
+// the definition when the attempt starts, so later changes to the definition
+// do not change how the attempt was scored.
+func (q *Quiz) GetScoring() ScoringStrategy {
+	return q.scoring
 }
 
 func (q *Quiz) GetStartTime() time.Time {
@@ -152,19 +613,273 @@ func (q *Quiz) GetQuestions() []Questioner {
 	return q.questions
 }
 
-// NewQuizFromDB creates a quiz from database data
-func NewQuizFromDB(id string, questions []Questioner, status QuizStatus, currentIndex int, score int, completed bool, startTime time.Time, creationDate time.Time, timeTaken time.Duration, correctCount int, history []QuestionResult) *Quiz {
-	return &Quiz{
-		Id:              id,
-		questions:       questions,
-		currentIndex:    currentIndex,
-		score:           score,
-		completed:       completed,
-		status:          status,
-		startTime:       startTime,
-		creationDate:    creationDate,
-		timeTaken:       timeTaken,
-		correctCount:    correctCount,
-		questionHistory: history,
+// shuffle puts the questions and their options in a random order as the
+// definition asks, using the attempt's random source.
+func (q *Quiz) shuffle() {
+	if q.definition.ShuffleQuestions {
+		questions := make([]Questioner, len(q.questions))
+		copy(questions, q.questions)
+		var pools []string
+		if q.questionPools != nil {
+			pools = make([]string, len(q.questionPools))
+			copy(pools, q.questionPools)
+		}
+		q.rand.Shuffle(len(questions), func(i, j int) {
+			questions[i], questions[j] = questions[j], questions[i]
+			if pools != nil {
+				pools[i], pools[j] = pools[j], pools[i]
+			}
+		})
+		q.questions = questions
+		q.questionPools = pools
+	}
+
+	if q.settings.ShuffleAnswers {
+		q.optionOrders = make([][]int, len(q.questions))
+		for i, question := range q.questions {
+			if shuffler, ok := question.(OptionShuffler); ok {
+				q.optionOrders[i] = shuffler.ShuffleOptions(q.rand)
+			}
+		}
+	}
+}
+
+// GetQuestionPool returns the ID of the pool the question at index was drawn
+// from, or "" if it is one of the definition's own questions.
+func (q *Quiz) GetQuestionPool(index int) string {
+	if index < 0 || index >= len(q.questionPools) {
+		return ""
+	}
+	return q.questionPools[index]
+}
+
+// GetOptionOrder returns the canonical option indices of the question at
+// index in the order they are presented, or nil if its options are shown in
+// their stored order.
+func (q *Quiz) GetOptionOrder(index int) []int {
+	if index < 0 || index >= len(q.optionOrders) {
+		return nil
+	}
+	return q.optionOrders[index]
+}
+
+// GetPresentedOptions returns the options of the question at index in the
+// order they are presented, or nil if it has no options.
+func (q *Quiz) GetPresentedOptions(index int) []string {
+	if index < 0 || index >= len(q.questions) {
+		return nil
+	}
+	shuffler, ok := q.questions[index].(OptionShuffler)
+	if !ok {
+		return nil
+	}
+	return presentedOptions(shuffler, q.GetOptionOrder(index))
+}
+
+// canonicalAnswer maps an answer to the question at index from the presented
+// option order back to the canonical one.
+func (q *Quiz) canonicalAnswer(index int, answer Answer) Answer {
+	order := q.GetOptionOrder(index)
+	shuffler, ok := q.questions[index].(OptionShuffler)
+	if !ok || order == nil {
+		return answer
+	}
+	return shuffler.CanonicalAnswer(answer, order)
+}
+
+// AddUser records that the user takes part in this attempt. An attempt may
+// be taken by a group of users.
+func (q *Quiz) AddUser(userID string) {
+	for _, id := range q.userIDs {
+		if id == userID {
+			return
+		}
 	}
+	q.userIDs = append(q.userIDs, userID)
+}
+
+// GetUserIDs returns the users taking part in this attempt.
+func (q *Quiz) GetUserIDs() []string {
+	return q.userIDs
+}
+
+// GetDefinition returns the definition this attempt was started from.
+func (q *Quiz) GetDefinition() *QuizDefinition {
+	return q.definition
+}
+
+// AttemptState is the stored state of an attempt, from which RestoreQuiz
+// rebuilds it.
+type AttemptState struct {
+	Id         string
+	Definition *QuizDefinition
+	// Scoring is the strategy the attempt was scored with. If it is nil the
+	// definition's strategy is used.
+	Scoring ScoringStrategy
+	// Settings are the rules the attempt follows. If they are nil the
+	// definition's current settings are used.
+	Settings     *AttemptSettings
+	UserIDs      []string
+	Questions    []Questioner
+	Status       QuizStatus
+	CurrentIndex int
+	// Score is the sum of the points awarded, as returned by TotalPoints.
+	Score        float64
+	Completed    bool
+	StartTime    time.Time
+	CreationDate time.Time
+	// PresentedAt is when the current question was presented. If it is zero
+	// the question is treated as presented when the attempt is restored.
+	PresentedAt time.Time
+	// QuestionPools holds the pool each question was drawn from, as returned
+	// by GetQuestionPool. It may be nil if no questions were drawn.
+	QuestionPools []string
+	// Flagged lists the indices of the questions flagged for review.
+	Flagged []int
+	// HintsUsed maps question indices to the number of hints revealed.
+	HintsUsed map[int]int
+	// TimeSpent maps question indices to the time they were shown before
+	// they were last presented, as returned by GetTimeSpent.
+	TimeSpent map[int]time.Duration
+	// OptionOrders holds the presented option order of each question, as
+	// returned by GetOptionOrder. It may be nil if no options were shuffled.
+	OptionOrders [][]int
+	// Candidates are the questions an adaptive attempt picks from and
+	// AdaptiveTrace how it picked each of its questions.
+	Candidates    []Questioner
+	AdaptiveTrace []AdaptiveStep
+	Seed          int64
+	// PausedAt is when a SAVED attempt was paused and PausedTime how long
+	// it was paused before that.
+	PausedAt     time.Time
+	PausedTime   time.Duration
+	TimeTaken    time.Duration
+	CorrectCount int
+	History      []QuestionResult
+}
+
+// State returns the attempt's state for storage.
+func (q *Quiz) State() AttemptState {
+	return AttemptState{
+		Id:            q.Id,
+		Definition:    q.definition,
+		Scoring:       q.scoring,
+		Settings:      &q.settings,
+		UserIDs:       q.userIDs,
+		Questions:     q.questions,
+		Status:        q.status,
+		CurrentIndex:  q.currentIndex,
+		Score:         q.score,
+		Completed:     q.completed,
+		StartTime:     q.startTime,
+		CreationDate:  q.creationDate,
+		PresentedAt:   q.presentedAt,
+		QuestionPools: q.questionPools,
+		Flagged:       q.FlaggedQuestions(),
+		HintsUsed:     q.hintsUsed,
+		TimeSpent:     q.timeSpent,
+		OptionOrders:  q.optionOrders,
+		Candidates:    q.candidates,
+		AdaptiveTrace: q.trace,
+		Seed:          q.seed,
+		PausedAt:      q.pausedAt,
+		PausedTime:    q.pausedTime,
+		TimeTaken:     q.timeTaken,
+		CorrectCount:  q.correctCount,
+		History:       q.questionHistory,
+	}
+}
+
+// RestoreQuiz rebuilds an attempt from its stored state, reading the time as
+// configured by opts. Attempts stored without a definition get an implicit
+// one, as with NewQuiz, and attempts stored without a seed get one from opts.
+func RestoreQuiz(state AttemptState, opts Options) *Quiz {
+	definition := state.Definition
+	if definition == nil {
+		definition = adHocDefinition(state.Id, state.Questions, opts)
+	}
+	scoring := state.Scoring
+	if scoring == nil {
+		scoring = definition.Strategy()
+	}
+	settings := definition.Settings()
+	if state.Settings != nil {
+		settings = *state.Settings
+		settings.RetryDecay = slices.Clone(settings.RetryDecay)
+	}
+	clock := opts.clock()
+	presentedAt := state.PresentedAt
+	if presentedAt.IsZero() {
+		presentedAt = clock.Now()
+	}
+	seed := state.Seed
+	if seed == 0 {
+		seed = opts.seed()
+	}
+	flagged := make(map[int]bool, len(state.Flagged))
+	for _, index := range state.Flagged {
+		flagged[index] = true
+	}
+	hintsUsed := make(map[int]int, len(state.HintsUsed))
+	for index, used := range state.HintsUsed {
+		hintsUsed[index] = used
+	}
+	timeSpent := make(map[int]time.Duration, len(state.TimeSpent))
+	for index, spent := range state.TimeSpent {
+		timeSpent[index] = spent
+	}
+	q := &Quiz{
+		Id:              state.Id,
+		definition:      definition,
+		scoring:         scoring,
+		settings:        settings,
+		userIDs:         state.UserIDs,
+		questions:       state.Questions,
+		questionPools:   state.QuestionPools,
+		flagged:         flagged,
+		hintsUsed:       hintsUsed,
+		timeSpent:       timeSpent,
+		optionOrders:    state.OptionOrders,
+		candidates:      state.Candidates,
+		trace:           state.AdaptiveTrace,
+		currentIndex:    state.CurrentIndex,
+		score:           state.Score,
+		completed:       state.Completed,
+		status:          state.Status,
+		clock:           clock,
+		seed:            seed,
+		rand:            NewRand(seed),
+		startTime:       state.StartTime,
+		creationDate:    state.CreationDate,
+		presentedAt:     presentedAt,
+		pausedAt:        state.PausedAt,
+		pausedTime:      state.PausedTime,
+		timeTaken:       state.TimeTaken,
+		correctCount:    state.CorrectCount,
+		questionHistory: state.History,
+	}
+	// Attempts stored before navigation kept the status of the question it
+	// moved to are AWAITING_ANSWER at questions that already have an answer
+	if q.status == AWAITING_ANSWER && q.latestResult(q.currentIndex) != nil {
+		q.status = ANSWERED
+	}
+	return q
+}
+
+// NewQuizFromDB creates a quiz from database data
+func NewQuizFromDB(id string, definition *QuizDefinition, questions []Questioner, status QuizStatus, currentIndex int, score float64, completed bool, startTime time.Time, creationDate time.Time, timeTaken time.Duration, correctCount int, history []QuestionResult) *Quiz {
+	return RestoreQuiz(AttemptState{
+		Id:           id,
+		Definition:   definition,
+		Questions:    questions,
+		Status:       status,
+		CurrentIndex: currentIndex,
+		Score:        score,
+		Completed:    completed,
+		StartTime:    startTime,
+		CreationDate: creationDate,
+		TimeTaken:    timeTaken,
+		CorrectCount: correctCount,
+		History:      history,
+	}, Options{})
 }
@@ -1,6 +1,7 @@
 package quiz
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -43,7 +44,7 @@ func TestNewQuiz(t *testing.T) {
 		t.Errorf("Expected 3 questions, got %d", quiz.AmountOfQuestions())
 	}
 	if quiz.TotalPoints() != 0 {
-		t.Errorf("Expected initial score 0, got %d", quiz.TotalPoints())
+		t.Errorf("Expected initial score 0, got %v", quiz.TotalPoints())
 	}
 	if quiz.IsCompleted() {
 		t.Error("Expected quiz not to be completed initially")
@@ -233,7 +234,7 @@ func TestSubmitAnswer(t *testing.T) {
 		t.Error("Expected correct answer to be accepted")
 	}
 	if quiz.TotalPoints() != 1 {
-		t.Errorf("Expected score 1, got %d", quiz.TotalPoints())
+		t.Errorf("Expected score 1, got %v", quiz.TotalPoints())
 	}
 
 	// Test incorrect answer
@@ -241,7 +242,7 @@ func TestSubmitAnswer(t *testing.T) {
 		t.Error("Expected incorrect answer to be rejected")
 	}
 	if quiz.TotalPoints() != 1 {
-		t.Errorf("Expected score to remain 1, got %d", quiz.TotalPoints())
+		t.Errorf("Expected score to remain 1, got %v", quiz.TotalPoints())
 	}
 
 	// Test after completion
@@ -276,3 +277,239 @@ func TestProgress(t *testing.T) {
 		t.Errorf("Expected progress 3/3, got %d/%d", current, total)
 	}
 }
+
+func TestQuestionAttempt(t *testing.T) {
+	questions := createTestQuestions()
+	quiz := NewQuiz("quiz1", questions)
+
+	before := time.Now()
+	quiz.SubmitAnswer("5")
+	attempt := quiz.GetQuestionHistory()[0].Attempt
+	if attempt.QuestionID != "mc1" {
+		t.Errorf("Expected question ID 'mc1', got '%s'", attempt.QuestionID)
+	}
+	if attempt.Answer != "5" {
+		t.Errorf("Expected answer '5', got '%s'", attempt.Answer)
+	}
+	if attempt.SubmittedAt.Before(before) {
+		t.Errorf("Expected submission time after %v, got %v", before, attempt.SubmittedAt)
+	}
+}
+
+func TestRegrade(t *testing.T) {
+	questions := createTestQuestions()
+	quiz := NewQuiz("quiz1", questions)
+
+	quiz.SubmitAnswer("5")
+	quiz.NextQuestion()
+	quiz.SubmitAnswer("true")
+	if quiz.GetScore() != 2 || quiz.GetCorrectCount() != 1 {
+		t.Fatalf("Expected score 2 with 1 correct answer, got %v with %d", quiz.GetScore(), quiz.GetCorrectCount())
+	}
+
+	// Nothing changes while the answer key is the same
+	if changed := quiz.Regrade(); changed != 0 {
+		t.Errorf("Expected no changes, got %d", changed)
+	}
+
+	// Fix the answer key of the first question
+	questions[0].(*MultiChoice).Answer = "5"
+	if changed := quiz.Regrade(); changed != 1 {
+		t.Errorf("Expected 1 change, got %d", changed)
+	}
+	if quiz.GetScore() != 3 {
+		t.Errorf("Expected score 3, got %v", quiz.GetScore())
+	}
+	if quiz.GetCorrectCount() != 2 {
+		t.Errorf("Expected 2 correct answers, got %d", quiz.GetCorrectCount())
+	}
+	if !quiz.GetQuestionHistory()[0].Correct {
+		t.Error("Expected first answer to be correct after regrading")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	questions := createTestQuestions()
+	d := NewQuizDefinition("def1", "Retries", []string{"mc1", "tf1", "fi1"})
+	d.MaxTries = 3
+	d.RetryDecay = []float64{1, 0.5, 0.25}
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	quiz, err := NewAttempt("a1", d, questions)
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+
+	if quiz.RemainingTries() != 3 {
+		t.Errorf("Expected 3 tries, got %d", quiz.RemainingTries())
+	}
+
+	// Wrong twice, then right on the third try for a quarter of the points
+	quiz.SubmitAnswer("3")
+	quiz.SubmitAnswer("5")
+	if quiz.RemainingTries() != 1 {
+		t.Errorf("Expected 1 try left, got %d", quiz.RemainingTries())
+	}
+	if !quiz.SubmitAnswer("4") {
+		t.Error("Expected the third try to be correct")
+	}
+	if quiz.RemainingTries() != 0 {
+		t.Errorf("Expected no tries left, got %d", quiz.RemainingTries())
+	}
+	var transitionErr *TransitionError
+	if _, err := quiz.Submit(TextAnswer("4")); !errors.Is(err, ErrNoTriesLeft) || !errors.As(err, &transitionErr) {
+		t.Errorf("Expected a TransitionError for ErrNoTriesLeft, got %v", err)
+	}
+	if quiz.GetScore() != 0.25 {
+		t.Errorf("Expected score 0.25, got %v", quiz.GetScore())
+	}
+
+	// Right on the second try of a question worth 2
+	quiz.NextQuestion()
+	quiz.SubmitAnswer("false")
+	quiz.SubmitAnswer("true")
+	if quiz.GetScore() != 1.25 {
+		t.Errorf("Expected score 1.25, got %v", quiz.GetScore())
+	}
+	if quiz.GetCorrectCount() != 2 {
+		t.Errorf("Expected 2 correct answers, got %d", quiz.GetCorrectCount())
+	}
+
+	history := quiz.GetQuestionHistory()
+	if len(history) != 5 {
+		t.Fatalf("Expected 5 history entries, got %d", len(history))
+	}
+	for i, try := range []int{1, 2, 3, 1, 2} {
+		if history[i].Try != try {
+			t.Errorf("Entry %d: expected try %d, got %d", i, try, history[i].Try)
+		}
+	}
+}
+
+func TestSingleTry(t *testing.T) {
+	questions := createTestQuestions()
+	quiz := NewQuiz("quiz1", questions)
+
+	if quiz.RemainingTries() != 1 {
+		t.Errorf("Expected 1 try, got %d", quiz.RemainingTries())
+	}
+	quiz.SubmitAnswer("3")
+	if _, err := quiz.Submit(TextAnswer("4")); !errors.Is(err, ErrNoTriesLeft) {
+		t.Errorf("Expected ErrNoTriesLeft, got %v", err)
+	}
+	if quiz.GetScore() != 0 || len(quiz.GetQuestionHistory()) != 1 {
+		t.Errorf("Expected the second answer to be ignored, got score %v", quiz.GetScore())
+	}
+}
+
+func newTimedAttempt(t *testing.T, configure func(d *QuizDefinition)) (*Quiz, *FakeClock) {
+	t.Helper()
+	d := NewQuizDefinition("def1", "Timed", []string{"mc1", "tf1", "fi1"})
+	configure(d)
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	clock := NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	quiz, err := NewAttemptWithOptions("a1", d, createTestQuestions(), Options{Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	return quiz, clock
+}
+
+func TestQuestionTimeLimit(t *testing.T) {
+	quiz, clock := newTimedAttempt(t, func(d *QuizDefinition) {})
+
+	// mc1 allows 30 seconds
+	clock.Advance(31 * time.Second)
+	if remaining, ok := quiz.QuestionRemainingTime(); !ok || remaining != 0 {
+		t.Errorf("Expected no time left, got %v", remaining)
+	}
+	result, err := quiz.Submit(TextAnswer("4"))
+	if err != nil {
+		t.Fatalf("Expected a late answer to be recorded, got %v", err)
+	}
+	if result.IsCorrect || result.ScoreAwarded != 0 {
+		t.Errorf("Expected no credit for a late answer, got %+v", result)
+	}
+	entry := quiz.GetQuestionHistory()[0]
+	if !entry.Late {
+		t.Error("Expected the answer to be marked late")
+	}
+	if entry.TimeTaken != 31*time.Second {
+		t.Errorf("Expected time taken 31s, got %v", entry.TimeTaken)
+	}
+
+	// tf1 allows 15 seconds, measured from when it is presented
+	quiz.NextQuestion()
+	clock.Advance(5 * time.Second)
+	if remaining, ok := quiz.QuestionRemainingTime(); !ok || remaining != 10*time.Second {
+		t.Errorf("Expected 10s left, got %v", remaining)
+	}
+	if !quiz.SubmitAnswer("true") {
+		t.Error("Expected an answer within the time limit to be correct")
+	}
+	if quiz.GetQuestionHistory()[1].TimeTaken != 5*time.Second {
+		t.Errorf("Expected time taken 5s, got %v", quiz.GetQuestionHistory()[1].TimeTaken)
+	}
+	if quiz.GetScore() != 2 {
+		t.Errorf("Expected score 2, got %v", quiz.GetScore())
+	}
+}
+
+func TestQuestionTimeLimitRejectsLateAnswers(t *testing.T) {
+	quiz, clock := newTimedAttempt(t, func(d *QuizDefinition) {
+		d.LatePolicy = REJECT_LATE
+	})
+
+	clock.Advance(31 * time.Second)
+	if _, err := quiz.Submit(TextAnswer("4")); err != ErrQuestionExpired {
+		t.Errorf("Expected ErrQuestionExpired, got %v", err)
+	}
+	if len(quiz.GetQuestionHistory()) != 0 {
+		t.Error("Expected the late answer not to be recorded")
+	}
+}
+
+func TestQuizTimeLimit(t *testing.T) {
+	quiz, clock := newTimedAttempt(t, func(d *QuizDefinition) {
+		d.TimeLimit = time.Minute
+	})
+
+	clock.Advance(20 * time.Second)
+	quiz.SubmitAnswer("4")
+	quiz.NextQuestion()
+	if remaining, ok := quiz.RemainingTime(); !ok || remaining != 40*time.Second {
+		t.Errorf("Expected 40s left, got %v", remaining)
+	}
+
+	clock.Advance(41 * time.Second)
+	if quiz.GetStatus() != AWAITING_ANSWER {
+		t.Errorf("Expected GetStatus not to expire the attempt, got %v", quiz.GetStatus())
+	}
+	if quiz.GetTimeTaken() != time.Minute {
+		t.Errorf("Expected time taken to stop at the limit, got %v", quiz.GetTimeTaken())
+	}
+	if !quiz.CheckExpiry() {
+		t.Error("Expected the attempt to expire")
+	}
+	if quiz.GetStatus() != EXPIRED {
+		t.Errorf("Expected status EXPIRED, got %v", quiz.GetStatus())
+	}
+	if !quiz.IsCompleted() {
+		t.Error("Expected an expired quiz to be completed")
+	}
+	if _, err := quiz.Submit(TextAnswer("true")); err != ErrQuizExpired {
+		t.Errorf("Expected ErrQuizExpired, got %v", err)
+	}
+	if quiz.NextQuestion() {
+		t.Error("Expected no next question after expiry")
+	}
+	if quiz.GetTimeTaken() != time.Minute {
+		t.Errorf("Expected time taken 1m, got %v", quiz.GetTimeTaken())
+	}
+	if quiz.GetScore() != 1 {
+		t.Errorf("Expected the score before expiry to be kept, got %v", quiz.GetScore())
+	}
+}
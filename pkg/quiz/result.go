@@ -0,0 +1,105 @@
+package quiz
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoResult is returned when asking for the result of an attempt that has
+// not finished, or whose responses still wait for a grader.
+var ErrNoResult = errors.New("quiz has no result until it is finished")
+
+// QuestionOutcome is the part of a QuizResult that covers one question of
+// the attempt. It copies the question's prompt and the latest try's grade
+// so the result still explains the score after the question is edited.
+type QuestionOutcome struct {
+	QuestionIndex int
+	QuestionID    string
+	Prompt        string
+	// Answered is false for questions that were skipped or not reached.
+	Answered     bool
+	Answer       string
+	Correct      bool
+	Pending      bool
+	Late         bool
+	Tries        int
+	HintsUsed    int
+	ScoreAwarded float64
+	MaxScore     float64
+	Feedback     string
+	TimeTaken    time.Duration
+}
+
+// QuizResult is the final outcome of a finished attempt. Score is the
+// attempt's score under its scoring strategy and MaxScore the points its
+// questions were worth. Percentage may exceed 100 when the strategy awards
+// bonus points. Passed is set when Percentage reaches the definition's
+// PassingScore, and always if the definition has none.
+type QuizResult struct {
+	AttemptID      string
+	DefinitionID   string
+	UserIDs        []string
+	Status         QuizStatus
+	ScoringMethod  ScoringMethod
+	Score          float64
+	MaxScore       float64
+	Percentage     float64
+	PassingScore   float64
+	Passed         bool
+	CompletionTime time.Duration
+	CompletedAt    time.Time
+	Breakdown      []QuestionOutcome
+}
+
+// Result returns the result of a FINISHED or EXPIRED attempt, or
+// ErrNoResult if the attempt is still running, was quit or waits for
+// manual grading.
+func (q *Quiz) Result() (*QuizResult, error) {
+	status := q.GetStatus()
+	if status != FINISHED && status != EXPIRED {
+		return nil, ErrNoResult
+	}
+
+	result := &QuizResult{
+		AttemptID:      q.Id,
+		DefinitionID:   q.definition.Id,
+		UserIDs:        q.userIDs,
+		Status:         status,
+		ScoringMethod:  q.scoring.Method(),
+		Score:          q.GetScore(),
+		PassingScore:   q.settings.PassingScore,
+		CompletionTime: q.timeTaken,
+		CompletedAt:    q.startTime.Add(q.pausedTime + q.timeTaken),
+		Breakdown:      make([]QuestionOutcome, len(q.questions)),
+	}
+	for i, question := range q.questions {
+		outcome := QuestionOutcome{
+			QuestionIndex: i,
+			QuestionID:    question.GetID(),
+			Prompt:        question.GetPrompt(),
+			HintsUsed:     q.hintsUsed[i],
+			MaxScore:      q.worth(question),
+		}
+		if latest := q.latestResult(i); latest != nil {
+			outcome.Answered = true
+			outcome.Answer = latest.Attempt.Answer
+			outcome.Correct = latest.Correct
+			outcome.Pending = latest.Pending
+			outcome.Late = latest.Late
+			outcome.Tries = latest.Try
+			outcome.HintsUsed = latest.HintsUsed
+			outcome.ScoreAwarded = latest.Grade.ScoreAwarded
+			outcome.MaxScore = latest.Grade.MaxScore
+			outcome.Feedback = latest.Grade.Feedback
+			outcome.TimeTaken = latest.TimeTaken
+		}
+		result.MaxScore += outcome.MaxScore
+		result.Breakdown[i] = outcome
+	}
+
+	if result.MaxScore > 0 {
+		result.Percentage = result.Score / result.MaxScore * 100
+	}
+	result.Passed = result.PassingScore == 0 || result.Percentage >= result.PassingScore
+	return result, nil
+}
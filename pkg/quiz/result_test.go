@@ -0,0 +1,131 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func newResultAttempt(t *testing.T, passingScore float64) (*Quiz, *FakeClock) {
+	t.Helper()
+	questions := []Questioner{
+		&MultiChoice{Id: "mc1", Prompt: "What is 2+2?", Options: []string{"3", "4"}, Difficulty: 4, Answer: "4"},
+		&TrueFalse{Id: "tf1", Prompt: "The sky is green", Difficulty: 2, Answer: false},
+		&FillIn{Id: "fi1", Prompt: "The capital of France is ___", Difficulty: 2, Answer: "Paris"},
+	}
+	d := NewQuizDefinition("def1", "Results", []string{"mc1", "tf1", "fi1"})
+	d.PassingScore = passingScore
+	d.TimeLimit = time.Hour
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	clock := NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	quiz, err := NewAttemptWithOptions("a1", d, questions, Options{Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	quiz.AddUser("u1")
+	return quiz, clock
+}
+
+func TestResult(t *testing.T) {
+	quiz, clock := newResultAttempt(t, 50)
+	if _, err := quiz.Result(); err != ErrNoResult {
+		t.Errorf("Expected ErrNoResult before finishing, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	quiz.SubmitAnswer("4")
+	quiz.NextQuestion()
+	clock.Advance(time.Minute)
+	quiz.SubmitAnswer("true")
+	quiz.NextQuestion()
+	clock.Advance(time.Minute)
+	quiz.NextQuestion()
+
+	result, err := quiz.Result()
+	if err != nil {
+		t.Fatalf("Failed to get result: %v", err)
+	}
+	if result.AttemptID != "a1" || result.DefinitionID != "def1" {
+		t.Errorf("Expected attempt a1 of def1, got %s of %s", result.AttemptID, result.DefinitionID)
+	}
+	if result.Score != 4 || result.MaxScore != 8 {
+		t.Errorf("Expected score 4 of 8, got %v of %v", result.Score, result.MaxScore)
+	}
+	if result.Percentage != 50 || !result.Passed {
+		t.Errorf("Expected 50%% and passed, got %v%% and %v", result.Percentage, result.Passed)
+	}
+	if result.CompletionTime != 3*time.Minute {
+		t.Errorf("Expected completion time 3m, got %v", result.CompletionTime)
+	}
+	if !result.CompletedAt.Equal(clock.Now()) {
+		t.Errorf("Expected completed at %v, got %v", clock.Now(), result.CompletedAt)
+	}
+	if result.Status != FINISHED || result.ScoringMethod != DIFFICULTY_WEIGHTED {
+		t.Errorf("Expected FINISHED and DIFFICULTY_WEIGHTED, got %s and %s", result.Status, result.ScoringMethod)
+	}
+	if len(result.UserIDs) != 1 || result.UserIDs[0] != "u1" {
+		t.Errorf("Expected users [u1], got %v", result.UserIDs)
+	}
+
+	if len(result.Breakdown) != 3 {
+		t.Fatalf("Expected 3 questions in the breakdown, got %d", len(result.Breakdown))
+	}
+	first, second, third := result.Breakdown[0], result.Breakdown[1], result.Breakdown[2]
+	if !first.Answered || !first.Correct || first.ScoreAwarded != 4 || first.Prompt != "What is 2+2?" {
+		t.Errorf("Expected a correct answer worth 4 points, got %+v", first)
+	}
+	if !second.Answered || second.Correct || second.Answer != "true" || second.MaxScore != 2 {
+		t.Errorf("Expected a wrong answer worth up to 2 points, got %+v", second)
+	}
+	if third.Answered || third.ScoreAwarded != 0 || third.MaxScore != 2 {
+		t.Errorf("Expected an unanswered question worth up to 2 points, got %+v", third)
+	}
+}
+
+func TestResultFailed(t *testing.T) {
+	quiz, _ := newResultAttempt(t, 75)
+	quiz.SubmitAnswer("4")
+	if err := quiz.Quit(); err != nil {
+		t.Fatalf("Failed to quit: %v", err)
+	}
+	if _, err := quiz.Result(); err != ErrNoResult {
+		t.Errorf("Expected ErrNoResult for a quit attempt, got %v", err)
+	}
+
+	quiz, clock := newResultAttempt(t, 75)
+	quiz.SubmitAnswer("4")
+	clock.Advance(2 * time.Hour)
+	quiz.CheckExpiry()
+	result, err := quiz.Result()
+	if err != nil {
+		t.Fatalf("Failed to get result: %v", err)
+	}
+	if result.Status != EXPIRED || result.Passed {
+		t.Errorf("Expected an EXPIRED attempt that failed, got %s and %v", result.Status, result.Passed)
+	}
+	if result.CompletionTime != time.Hour {
+		t.Errorf("Expected completion time 1h, got %v", result.CompletionTime)
+	}
+}
+
+func TestResultAwaitingGrading(t *testing.T) {
+	questions := []Questioner{&Essay{Id: "e1", Prompt: "Describe the water cycle", Difficulty: 5}}
+	quiz := NewQuiz("q1", questions)
+	quiz.SubmitAnswer("Evaporation and rain")
+	quiz.NextQuestion()
+	if _, err := quiz.Result(); err != ErrNoResult {
+		t.Errorf("Expected ErrNoResult while awaiting grading, got %v", err)
+	}
+
+	if err := quiz.GradeResponse(0, 4, "Good"); err != nil {
+		t.Fatalf("Failed to grade response: %v", err)
+	}
+	result, err := quiz.Result()
+	if err != nil {
+		t.Fatalf("Failed to get result: %v", err)
+	}
+	if result.Percentage != 80 || result.Breakdown[0].Feedback != "Good" {
+		t.Errorf("Expected 80%% with feedback, got %v%% and %q", result.Percentage, result.Breakdown[0].Feedback)
+	}
+}
@@ -0,0 +1,209 @@
+package quiz
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ScoringStrategy turns the grade of an answer into the points it earns. The
+// grades passed to Score are worth the question's difficulty, as returned by
+// Evaluate; the strategy returns the grade worth the points the question is
+// worth under it. A definition's RetryDecay and HintPenalty reduce the
+// points a strategy awards, but not the points it deducts.
+type ScoringStrategy interface {
+	// Method names the strategy so attempts can record how they were scored.
+	Method() ScoringMethod
+	// Score returns grade rescored for question, answered in timeSpent.
+	Score(question Questioner, grade GradeResult, timeSpent time.Duration) GradeResult
+	// Total turns the sum of the question scores into the attempt's score.
+	Total(points float64) float64
+}
+
+// NewScoringStrategy returns the built-in strategy for method with its
+// default settings, or nil if method is not built in. An empty method is
+// scored by difficulty.
+func NewScoringStrategy(method ScoringMethod) ScoringStrategy {
+	switch method {
+	case "", DIFFICULTY_WEIGHTED:
+		return &DifficultyWeighted{}
+	case POINTS_PER_QUESTION:
+		return &PointsPerQuestion{}
+	case NEGATIVE_MARKING:
+		return &NegativeMarking{}
+	case TIME_BONUS:
+		return &TimeBonus{}
+	case CUSTOM_POINTS:
+		return &CustomPoints{}
+	}
+	return nil
+}
+
+// rescore returns grade worth maxScore points, keeping the share of the
+// points that was awarded.
+func rescore(grade GradeResult, maxScore float64) GradeResult {
+	grade.ScoreAwarded = grade.Fraction() * maxScore
+	grade.MaxScore = maxScore
+	return grade
+}
+
+// DifficultyWeighted awards each question up to its difficulty in points.
+type DifficultyWeighted struct{}
+
+func (DifficultyWeighted) Method() ScoringMethod {
+	return DIFFICULTY_WEIGHTED
+}
+
+func (DifficultyWeighted) Score(question Questioner, grade GradeResult, timeSpent time.Duration) GradeResult {
+	return grade
+}
+
+func (DifficultyWeighted) Total(points float64) float64 {
+	return points
+}
+
+// PointsPerQuestion awards every question up to Points, regardless of its
+// difficulty. Zero Points awards one point per question.
+type PointsPerQuestion struct {
+	Points float64 `json:"points"`
+}
+
+func (s PointsPerQuestion) Method() ScoringMethod {
+	return POINTS_PER_QUESTION
+}
+
+// Validate checks that the strategy can score answers.
+func (s PointsPerQuestion) Validate() error {
+	if s.Points < 0 {
+		return fmt.Errorf("points per question must not be negative, got %v", s.Points)
+	}
+	return nil
+}
+
+func (s PointsPerQuestion) Score(question Questioner, grade GradeResult, timeSpent time.Duration) GradeResult {
+	points := s.Points
+	if points == 0 {
+		points = 1
+	}
+	return rescore(grade, points)
+}
+
+func (s PointsPerQuestion) Total(points float64) float64 {
+	return points
+}
+
+// NegativeMarking deducts Penalty points for every wrong answer that earned
+// nothing; partly correct answers keep their partial credit. Questions are
+// worth Points each, or their difficulty if Points is zero. With FloorAtZero
+// the attempt's score never drops below zero.
+type NegativeMarking struct {
+	Points      float64 `json:"points"`
+	Penalty     float64 `json:"penalty"`
+	FloorAtZero bool    `json:"floorAtZero"`
+}
+
+func (s NegativeMarking) Method() ScoringMethod {
+	return NEGATIVE_MARKING
+}
+
+// Validate checks that the strategy can score answers.
+func (s NegativeMarking) Validate() error {
+	if s.Points < 0 {
+		return fmt.Errorf("points per question must not be negative, got %v", s.Points)
+	}
+	if s.Penalty < 0 {
+		return fmt.Errorf("negative marking penalty must not be negative, got %v", s.Penalty)
+	}
+	return nil
+}
+
+func (s NegativeMarking) Score(question Questioner, grade GradeResult, timeSpent time.Duration) GradeResult {
+	if s.Points > 0 {
+		grade = rescore(grade, s.Points)
+	}
+	if !grade.IsCorrect && !grade.Pending && grade.ScoreAwarded == 0 {
+		grade.ScoreAwarded = -s.Penalty
+	}
+	return grade
+}
+
+func (s NegativeMarking) Total(points float64) float64 {
+	if s.FloorAtZero {
+		return math.Max(points, 0)
+	}
+	return points
+}
+
+// TimeBonus awards correct answers up to Bonus times their points on top,
+// shrinking the bonus linearly as the question's time limit runs out.
+// Questions without a time limit measure against Window instead, and earn
+// no bonus if Window is zero. A question's bonus does not count towards the
+// points it is worth, so scores may exceed 100%.
+type TimeBonus struct {
+	Bonus  float64       `json:"bonus"`
+	Window time.Duration `json:"window"`
+}
+
+func (s TimeBonus) Method() ScoringMethod {
+	return TIME_BONUS
+}
+
+// Validate checks that the strategy can score answers.
+func (s TimeBonus) Validate() error {
+	if s.Bonus < 0 {
+		return fmt.Errorf("time bonus must not be negative, got %v", s.Bonus)
+	}
+	if s.Window < 0 {
+		return fmt.Errorf("time bonus window must not be negative, got %v", s.Window)
+	}
+	return nil
+}
+
+func (s TimeBonus) Score(question Questioner, grade GradeResult, timeSpent time.Duration) GradeResult {
+	window := question.GetTimeLimit()
+	if window <= 0 {
+		window = s.Window
+	}
+	if !grade.IsCorrect || window <= 0 {
+		return grade
+	}
+	if left := 1 - float64(timeSpent)/float64(window); left > 0 {
+		grade.ScoreAwarded += grade.ScoreAwarded * s.Bonus * left
+	}
+	return grade
+}
+
+func (s TimeBonus) Total(points float64) float64 {
+	return points
+}
+
+// CustomPoints awards each question up to the points set for its ID.
+// Questions without an entry are worth their difficulty.
+type CustomPoints struct {
+	Points map[string]float64 `json:"points"`
+}
+
+func (s CustomPoints) Method() ScoringMethod {
+	return CUSTOM_POINTS
+}
+
+// Validate checks that the strategy can score answers.
+func (s CustomPoints) Validate() error {
+	for questionID, points := range s.Points {
+		if points < 0 {
+			return fmt.Errorf("question %s must not be worth negative points, got %v", questionID, points)
+		}
+	}
+	return nil
+}
+
+func (s CustomPoints) Score(question Questioner, grade GradeResult, timeSpent time.Duration) GradeResult {
+	if points, ok := s.Points[question.GetID()]; ok {
+		return rescore(grade, points)
+	}
+	return grade
+}
+
+func (s CustomPoints) Total(points float64) float64 {
+	return points
+}
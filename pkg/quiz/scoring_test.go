@@ -0,0 +1,195 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+)
+
+func newScoredAttempt(t *testing.T, scoring ScoringStrategy) (*Quiz, *FakeClock) {
+	t.Helper()
+	questions := []Questioner{
+		&MultiChoice{Id: "mc1", Prompt: "What is 2+2?", Options: []string{"3", "4"}, Difficulty: 4, Answer: "4", TimeLimit: 10 * time.Second},
+		&TrueFalse{Id: "tf1", Prompt: "The sky is green", Difficulty: 2, Answer: false},
+		&FillIn{Id: "fi1", Prompt: "The capital of France is ___", Difficulty: 3, Answer: "Paris"},
+	}
+	d := NewQuizDefinition("def1", "Scored", []string{"mc1", "tf1", "fi1"})
+	d.SetScoring(scoring)
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	clock := NewFakeClock(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	quiz, err := NewAttemptWithOptions("a1", d, questions, Options{Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	return quiz, clock
+}
+
+// answerAll answers the attempt's questions in turn.
+func answerAll(quiz *Quiz, answers ...string) {
+	for i, answer := range answers {
+		quiz.SubmitAnswer(answer)
+		if i < len(answers)-1 {
+			quiz.NextQuestion()
+		}
+	}
+}
+
+func TestScoringStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		scoring  ScoringStrategy
+		answers  []string
+		score    float64
+		maxScore float64
+	}{
+		{"difficulty weighted", &DifficultyWeighted{}, []string{"4", "true", "Paris"}, 7, 4},
+		{"points per question", &PointsPerQuestion{Points: 2}, []string{"4", "true", "Paris"}, 4, 2},
+		{"one point per question", &PointsPerQuestion{}, []string{"4", "false", "Paris"}, 3, 1},
+		{"negative marking", &NegativeMarking{Penalty: 1}, []string{"3", "true", "Paris"}, 1, 4},
+		{"negative marking per point", &NegativeMarking{Points: 1, Penalty: 0.25}, []string{"3", "true", "Paris"}, 0.5, 1},
+		{"negative marking below zero", &NegativeMarking{Penalty: 2}, []string{"3", "true", "Rome"}, -6, 4},
+		{"negative marking floored", &NegativeMarking{Penalty: 2, FloorAtZero: true}, []string{"3", "true", "Rome"}, 0, 4},
+		{"custom points", &CustomPoints{Points: map[string]float64{"mc1": 10, "tf1": 1}}, []string{"4", "false", "Paris"}, 14, 10},
+	}
+
+	for _, tt := range tests {
+		quiz, _ := newScoredAttempt(t, tt.scoring)
+		answerAll(quiz, tt.answers...)
+		if quiz.GetScore() != tt.score {
+			t.Errorf("%s: Expected score %v, got %v", tt.name, tt.score, quiz.GetScore())
+		}
+		if got := quiz.GetQuestionHistory()[0].Grade.MaxScore; got != tt.maxScore {
+			t.Errorf("%s: Expected max score %v, got %v", tt.name, tt.maxScore, got)
+		}
+		if quiz.GetScoring().Method() != tt.scoring.Method() {
+			t.Errorf("%s: Expected scoring method %s, got %s", tt.name, tt.scoring.Method(), quiz.GetScoring().Method())
+		}
+	}
+}
+
+func TestNegativeMarkingFloor(t *testing.T) {
+	quiz, _ := newScoredAttempt(t, &NegativeMarking{Penalty: 2, FloorAtZero: true})
+	answerAll(quiz, "3", "true", "Paris")
+
+	// The floor applies to the attempt, not to each question
+	if quiz.TotalPoints() != -1 {
+		t.Errorf("Expected total points -1, got %v", quiz.TotalPoints())
+	}
+	if quiz.GetScore() != 0 {
+		t.Errorf("Expected score 0, got %v", quiz.GetScore())
+	}
+	if got := quiz.GetQuestionHistory()[0].Grade.ScoreAwarded; got != -2 {
+		t.Errorf("Expected -2 points for the wrong answer, got %v", got)
+	}
+}
+
+func TestTimeBonus(t *testing.T) {
+	quiz, clock := newScoredAttempt(t, &TimeBonus{Bonus: 0.5, Window: 20 * time.Second})
+
+	// Answered with three quarters of the 10s limit left
+	clock.Advance(2500 * time.Millisecond)
+	quiz.SubmitAnswer("4")
+	if got := quiz.GetQuestionHistory()[0].Grade.ScoreAwarded; got != 5.5 {
+		t.Errorf("Expected 5.5 points, got %v", got)
+	}
+
+	// Questions without a limit are measured against the window
+	quiz.NextQuestion()
+	clock.Advance(10 * time.Second)
+	quiz.SubmitAnswer("false")
+	if got := quiz.GetQuestionHistory()[1].Grade.ScoreAwarded; got != 2.5 {
+		t.Errorf("Expected 2.5 points, got %v", got)
+	}
+
+	// No bonus once the window has passed or for wrong answers
+	quiz.NextQuestion()
+	clock.Advance(30 * time.Second)
+	quiz.SubmitAnswer("Paris")
+	if got := quiz.GetQuestionHistory()[2].Grade.ScoreAwarded; got != 3 {
+		t.Errorf("Expected 3 points, got %v", got)
+	}
+}
+
+func TestManualGradeScale(t *testing.T) {
+	questions := []Questioner{&Essay{Id: "e1", Prompt: "Describe the water cycle", Difficulty: 5}}
+	d := NewQuizDefinition("def1", "Essays", []string{"e1"})
+	d.SetScoring(&PointsPerQuestion{Points: 10})
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	quiz, err := NewAttempt("a1", d, questions)
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	quiz.SubmitAnswer("Water evaporates and falls as rain.")
+
+	// Points are given on the strategy's scale, not the difficulty's
+	if err := quiz.GradeResponse(0, 11, ""); err == nil {
+		t.Error("Expected error for points above what the question is worth")
+	}
+	if err := quiz.GradeResponse(0, 8, "Good"); err != nil {
+		t.Fatalf("Failed to grade response: %v", err)
+	}
+	grade := quiz.GetQuestionHistory()[0].Grade
+	if grade.ScoreAwarded != 8 || grade.MaxScore != 10 || grade.IsCorrect {
+		t.Errorf("Expected 8 of 10 points, got %+v", grade)
+	}
+	if quiz.GetScore() != 8 {
+		t.Errorf("Expected score 8, got %v", quiz.GetScore())
+	}
+}
+
+func TestScoringValidation(t *testing.T) {
+	invalid := []ScoringStrategy{
+		&PointsPerQuestion{Points: -1},
+		&NegativeMarking{Penalty: -1},
+		&TimeBonus{Bonus: -0.5},
+		&CustomPoints{Points: map[string]float64{"mc1": -2}},
+	}
+	for _, scoring := range invalid {
+		d := NewQuizDefinition("def1", "Scored", []string{"mc1"})
+		d.SetScoring(scoring)
+		if err := d.Validate(); err == nil {
+			t.Errorf("Expected an error for %+v", scoring)
+		}
+	}
+
+	d := NewQuizDefinition("def1", "Scored", []string{"mc1"})
+	d.ScoringMethod = "HIGHEST_WINS"
+	if err := d.Validate(); err == nil {
+		t.Errorf("Expected an error for an unknown scoring method")
+	}
+
+	d.ScoringMethod = POINTS_PER_QUESTION
+	if err := d.Validate(); err != nil {
+		t.Errorf("Expected the built-in method to validate, got %v", err)
+	}
+	if d.Strategy().Method() != POINTS_PER_QUESTION {
+		t.Errorf("Expected strategy POINTS_PER_QUESTION, got %s", d.Strategy().Method())
+	}
+
+	d.Scoring = &NegativeMarking{}
+	if err := d.Validate(); err == nil {
+		t.Errorf("Expected an error for a strategy that does not match the method")
+	}
+}
+
+func TestScoringSurvivesRestore(t *testing.T) {
+	quiz, _ := newScoredAttempt(t, &NegativeMarking{Penalty: 1})
+	answerAll(quiz, "3")
+
+	// Changing the definition does not rescore the attempt
+	quiz.GetDefinition().SetScoring(&PointsPerQuestion{})
+	restored := RestoreQuiz(quiz.State(), Options{})
+	if restored.GetScoring().Method() != NEGATIVE_MARKING {
+		t.Errorf("Expected scoring method NEGATIVE_MARKING, got %s", restored.GetScoring().Method())
+	}
+	if restored.GetScore() != -1 {
+		t.Errorf("Expected score -1, got %v", restored.GetScore())
+	}
+	restored.Regrade()
+	if restored.GetScore() != -1 {
+		t.Errorf("Expected score -1 after regrading, got %v", restored.GetScore())
+	}
+}
@@ -0,0 +1,74 @@
+package quiz
+
+import "math/rand"
+
+// OptionShuffler is implemented by questions whose options can be shown in a
+// different order in each attempt. An option order lists canonical option
+// indices in the order they are presented.
+type OptionShuffler interface {
+	// GetOptions returns the options in their canonical order.
+	GetOptions() []string
+	// ShuffleOptions returns a random option order. Pinned options keep
+	// their canonical position.
+	ShuffleOptions(r *rand.Rand) []int
+	// CanonicalAnswer maps an answer given against the presented order back
+	// to the canonical options so it can be graded.
+	CanonicalAnswer(answer Answer, order []int) Answer
+}
+
+// shuffleOptions returns a random order of n options in which the pinned
+// indices stay in place.
+func shuffleOptions(r *rand.Rand, n int, pinned []int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	isPinned := make(map[int]bool, len(pinned))
+	for _, index := range pinned {
+		isPinned[index] = true
+	}
+	var free []int
+	for i := range order {
+		if !isPinned[i] {
+			free = append(free, i)
+		}
+	}
+
+	r.Shuffle(len(free), func(i, j int) {
+		order[free[i]], order[free[j]] = order[free[j]], order[free[i]]
+	})
+	return order
+}
+
+// canonicalIndices maps option indices chosen against the presented order
+// back to canonical indices. Indices outside the order are kept so they are
+// still rejected when graded.
+func canonicalIndices(answer string, order []int) Answer {
+	indices, err := ParseIndices(answer)
+	if err != nil {
+		return TextAnswer(answer)
+	}
+	canonical := make(IndicesAnswer, len(indices))
+	for i, index := range indices {
+		if index >= 0 && index < len(order) {
+			canonical[i] = order[index]
+		} else {
+			canonical[i] = index
+		}
+	}
+	return canonical
+}
+
+// presentedOptions returns the options of s in the given order.
+func presentedOptions(s OptionShuffler, order []int) []string {
+	options := s.GetOptions()
+	if len(order) != len(options) {
+		return options
+	}
+	presented := make([]string, len(order))
+	for i, index := range order {
+		presented[i] = options[index]
+	}
+	return presented
+}
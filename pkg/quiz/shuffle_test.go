@@ -0,0 +1,153 @@
+package quiz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createShuffleQuestions() []Questioner {
+	return []Questioner{
+		&MultiChoice{
+			Id:            "mc1",
+			Prompt:        "Which are prime?",
+			Options:       []string{"2", "3", "4", "5", "All of the above"},
+			PinnedOptions: []int{4},
+			Difficulty:    1,
+			Answer:        "3",
+		},
+		&MultiResponse{
+			Id:                   "mr1",
+			Prompt:               "Select the even numbers",
+			Options:              []string{"1", "2", "3", "4", "None of these"},
+			PinnedOptions:        []int{4},
+			Difficulty:           2,
+			CorrectAnswerIndices: []int{1, 3},
+		},
+		&TrueFalse{Id: "tf1", Prompt: "The sky is blue", Difficulty: 1, Answer: true},
+		&FillIn{Id: "fi1", Prompt: "The capital of France is ___", Difficulty: 1, Answer: "Paris"},
+		&Ordering{Id: "o1", Prompt: "Order from smallest", Items: []string{"1", "2", "3"}, CorrectOrderIndices: []int{0, 1, 2}, Difficulty: 1},
+	}
+}
+
+func newShuffledAttempt(t *testing.T, id string, seed int64) *Quiz {
+	t.Helper()
+	d := NewQuizDefinition("def1", "Shuffled", []string{"mc1", "mr1", "tf1", "fi1", "o1"})
+	d.ShuffleQuestions = true
+	d.ShuffleAnswers = true
+	if err := d.Publish(); err != nil {
+		t.Fatalf("Failed to publish definition: %v", err)
+	}
+	quiz, err := NewAttemptWithOptions(id, d, createShuffleQuestions(), Options{Seed: seed})
+	if err != nil {
+		t.Fatalf("Failed to start attempt: %v", err)
+	}
+	return quiz
+}
+
+func TestShuffleOptionsPinned(t *testing.T) {
+	r := NewRand(1)
+	for i := 0; i < 50; i++ {
+		order := shuffleOptions(r, 5, []int{4})
+		if order[4] != 4 {
+			t.Fatalf("Expected pinned option to stay last, got %v", order)
+		}
+		seen := make(map[int]bool)
+		for _, index := range order {
+			seen[index] = true
+		}
+		if len(seen) != 5 {
+			t.Fatalf("Expected a permutation of 5 options, got %v", order)
+		}
+	}
+}
+
+func TestShuffledAttempt(t *testing.T) {
+	first := newShuffledAttempt(t, "a1", 99)
+	second := newShuffledAttempt(t, "a2", 99)
+
+	var firstIDs, secondIDs []string
+	for i, question := range first.GetQuestions() {
+		firstIDs = append(firstIDs, question.GetID())
+		secondIDs = append(secondIDs, second.GetQuestions()[i].GetID())
+		if !reflect.DeepEqual(first.GetOptionOrder(i), second.GetOptionOrder(i)) {
+			t.Errorf("Expected the same option order for the same seed, got %v and %v",
+				first.GetOptionOrder(i), second.GetOptionOrder(i))
+		}
+	}
+	if !reflect.DeepEqual(firstIDs, secondIDs) {
+		t.Errorf("Expected the same question order for the same seed, got %v and %v", firstIDs, secondIDs)
+	}
+
+	// Only questions with options get an option order
+	for i, question := range first.GetQuestions() {
+		_, hasOptions := question.(OptionShuffler)
+		if hasOptions != (first.GetOptionOrder(i) != nil) {
+			t.Errorf("Expected an option order only for questions with options, got %v for %s",
+				first.GetOptionOrder(i), question.GetID())
+		}
+	}
+
+	// The definition keeps the canonical order
+	if first.GetDefinition().QuestionIDs[0] != "mc1" {
+		t.Errorf("Expected the definition to keep its order, got %v", first.GetDefinition().QuestionIDs)
+	}
+}
+
+func TestShuffledAnswersAreMappedBack(t *testing.T) {
+	quiz := newShuffledAttempt(t, "a1", 7)
+
+	for i, question := range quiz.GetQuestions() {
+		switch question := question.(type) {
+		case *MultiChoice:
+			presented := quiz.GetPresentedOptions(i)
+			if presented[len(presented)-1] != "All of the above" {
+				t.Errorf("Expected the pinned option last, got %v", presented)
+			}
+			result, err := quiz.Submit(TextAnswer("3"))
+			if err != nil || !result.IsCorrect {
+				t.Errorf("Expected the chosen option to be correct, got %+v, %v", result, err)
+			}
+		case *MultiResponse:
+			// Select the even numbers by their presented positions
+			var selected IndicesAnswer
+			for position, option := range quiz.GetPresentedOptions(i) {
+				if option == "2" || option == "4" {
+					selected = append(selected, position)
+				}
+			}
+			result, err := quiz.Submit(selected)
+			if err != nil || !result.IsCorrect {
+				t.Errorf("Expected the presented selection to be correct, got %+v, %v", result, err)
+			}
+			if got := quiz.GetQuestionHistory()[len(quiz.GetQuestionHistory())-1].Attempt.Answer; got != "1,3" && got != "3,1" {
+				t.Errorf("Expected the canonical answer to be stored, got %q", got)
+			}
+			if !question.CheckAnswer("1,3") {
+				t.Errorf("Expected the canonical answer key to be unchanged")
+			}
+		case *Ordering:
+			// Arrange the items by their presented positions
+			presented := quiz.GetPresentedOptions(i)
+			if reflect.DeepEqual(presented, question.Items) {
+				t.Errorf("Expected the items not to be shown in the correct order, got %v", presented)
+			}
+			var arranged IndicesAnswer
+			for _, item := range question.Items {
+				for position, option := range presented {
+					if option == item {
+						arranged = append(arranged, position)
+					}
+				}
+			}
+			result, err := quiz.Submit(arranged)
+			if err != nil || !result.IsCorrect {
+				t.Errorf("Expected the presented arrangement to be correct, got %+v, %v", result, err)
+			}
+		default:
+			if quiz.GetPresentedOptions(i) != nil {
+				t.Errorf("Expected no options for %s", question.GetID())
+			}
+		}
+		quiz.NextQuestion()
+	}
+}
@@ -0,0 +1,72 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPermissionDenied is returned when a user lacks the role an action needs.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Role is a level of access a user has within the engine.
+type Role string
+
+const (
+	// AUTHOR users create and edit questions and quiz definitions.
+	AUTHOR Role = "AUTHOR"
+	// GRADER users grade manually graded responses.
+	GRADER Role = "GRADER"
+	// TAKER users attempt quizzes.
+	TAKER Role = "TAKER"
+	// ADMIN users may do everything the other roles can.
+	ADMIN Role = "ADMIN"
+)
+
+// User is a person or entity interacting with the engine, typically someone
+// taking a quiz. Email uniquely identifies the user.
+type User struct {
+	Id          string    `json:"id"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"displayName"`
+	Roles       []Role    `json:"roles"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// NewUser creates a user with the given roles.
+func NewUser(id, email, displayName string, roles ...Role) *User {
+	return NewUserWithOptions(id, email, displayName, Options{}, roles...)
+}
+
+// NewUserWithOptions creates a user like NewUser, reading its creation time
+// from the clock configured by opts.
+func NewUserWithOptions(id, email, displayName string, opts Options, roles ...Role) *User {
+	now := opts.clock().Now()
+	return &User{
+		Id:          id,
+		Email:       email,
+		DisplayName: displayName,
+		Roles:       roles,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// HasRole reports whether the user has role. Admins have every role.
+func (u *User) HasRole(role Role) bool {
+	for _, r := range u.Roles {
+		if r == role || r == ADMIN {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns an error wrapping ErrPermissionDenied unless the user has role.
+func (u *User) Authorize(role Role) error {
+	if !u.HasRole(role) {
+		return fmt.Errorf("user %s is not a %s: %w", u.Id, role, ErrPermissionDenied)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package quiz
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUserRoles(t *testing.T) {
+	author := NewUser("u1", "ada@example.com", "Ada", AUTHOR)
+	if !author.HasRole(AUTHOR) {
+		t.Error("Expected author to have the AUTHOR role")
+	}
+	if author.HasRole(GRADER) {
+		t.Error("Expected author not to have the GRADER role")
+	}
+	if err := author.Authorize(GRADER); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied, got %v", err)
+	}
+	if err := author.Authorize(AUTHOR); err != nil {
+		t.Errorf("Expected author to be authorized, got %v", err)
+	}
+
+	admin := NewUser("u2", "root@example.com", "Root", ADMIN)
+	for _, role := range []Role{AUTHOR, GRADER, TAKER, ADMIN} {
+		if !admin.HasRole(role) {
+			t.Errorf("Expected admin to have the %s role", role)
+		}
+	}
+}
+
+func TestAttemptUsers(t *testing.T) {
+	q := NewQuiz("quiz1", createTestQuestions())
+	q.AddUser("u1")
+	q.AddUser("u2")
+	q.AddUser("u1")
+
+	users := q.GetUserIDs()
+	if len(users) != 2 || users[0] != "u1" || users[1] != "u2" {
+		t.Errorf("Expected users [u1 u2], got %v", users)
+	}
+}
+
+func TestNewUserClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	user := NewUserWithOptions("u1", "ada@example.com", "Ada", Options{Clock: NewFakeClock(start)}, TAKER)
+	if !user.CreatedAt.Equal(start) || !user.UpdatedAt.Equal(start) {
+		t.Errorf("Expected the user to be created at %v, got %v and %v", start, user.CreatedAt, user.UpdatedAt)
+	}
+	if !user.HasRole(TAKER) {
+		t.Errorf("Expected the user to be a TAKER")
+	}
+}